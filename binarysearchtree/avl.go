@@ -0,0 +1,217 @@
+package binarysearchtree
+
+import "fmt"
+
+// AVLTree is a self-balancing binary search tree. The heights of the
+// two subtrees of every node differ by at most one, so the height of
+// the tree stays O(log n) whatever order the keys are inserted in.
+type AVLTree struct {
+	tree
+}
+
+// Insert adds the given key and value to the tree, replacing
+// the value if the key already exists.
+func (t *AVLTree) Insert(key int, value interface{}) {
+	var added bool
+	t.root = avlInsert(t.root, key, value, &added)
+	if added {
+		t.size++
+	}
+}
+
+func avlInsert(n *Node, key int, value interface{}, added *bool) *Node {
+	if n == nil {
+		*added = true
+		return &Node{Key: key, Value: value, height: 1}
+	}
+
+	switch {
+	case key < n.Key:
+		n.Left = avlInsert(n.Left, key, value, added)
+	case key > n.Key:
+		n.Right = avlInsert(n.Right, key, value, added)
+	default:
+		n.Value = value
+		return n
+	}
+	return rebalance(n)
+}
+
+// Delete removes the key from the tree and returns
+// the value it was associated with.
+func (t *AVLTree) Delete(key int) (interface{}, bool) {
+	n := t.root.Exact(key)
+	if n == nil {
+		return nil, false
+	}
+
+	// avlRemove may copy another node into n, so take the value first.
+	value := n.Value
+	t.root = avlRemove(t.root, key)
+	t.size--
+	return value, true
+}
+
+func avlRemove(n *Node, key int) *Node {
+	if n == nil {
+		return nil
+	}
+
+	switch {
+	case key < n.Key:
+		n.Left = avlRemove(n.Left, key)
+	case key > n.Key:
+		n.Right = avlRemove(n.Right, key)
+	default:
+		if n.Left == nil {
+			return n.Right
+		}
+		if n.Right == nil {
+			return n.Left
+		}
+
+		smallestRight := n.Right.Min()
+		n.Key, n.Value = smallestRight.Key, smallestRight.Value
+		n.Right = avlRemove(n.Right, n.Key)
+	}
+	return rebalance(n)
+}
+
+// Height returns the number of nodes on the longest path from the
+// root to a leaf, zero for an empty tree.
+func (t *AVLTree) Height() int {
+	return nodeHeight(t.root)
+}
+
+// Validate returns an error if the tree is not ordered, a stored height
+// or size is wrong, or the subtrees of any node differ in height by
+// more than one.
+func (t *AVLTree) Validate() error {
+	_, count, err := validateAVL(t.root, nil, nil)
+	if err != nil {
+		return err
+	}
+	if count != t.size {
+		return fmt.Errorf("expected %d nodes, counted %d", t.size, count)
+	}
+	return nil
+}
+
+// validateAVL checks the subtree rooted at n, whose keys must lie
+// between lo and hi, and returns its height and node count.
+func validateAVL(n *Node, lo, hi *int) (int, int, error) {
+	if n == nil {
+		return 0, 0, nil
+	}
+	if (lo != nil && n.Key <= *lo) || (hi != nil && n.Key >= *hi) {
+		return 0, 0, fmt.Errorf("key %d is out of order", n.Key)
+	}
+
+	lh, lc, err := validateAVL(n.Left, lo, &n.Key)
+	if err != nil {
+		return 0, 0, err
+	}
+	rh, rc, err := validateAVL(n.Right, &n.Key, hi)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	h := 1 + max(lh, rh)
+	if n.height != h {
+		return 0, 0, fmt.Errorf("key %d has height %d, expected %d", n.Key, n.height, h)
+	}
+	if bf := lh - rh; bf < -1 || bf > 1 {
+		return 0, 0, fmt.Errorf("key %d has balance factor %d", n.Key, bf)
+	}
+	if n.desc != lc+rc {
+		return 0, 0, fmt.Errorf("key %d has %d descendants, expected %d", n.Key, n.desc, lc+rc)
+	}
+	return h, lc + rc + 1, nil
+}
+
+func nodeHeight(n *Node) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func updateHeight(n *Node) {
+	n.height = 1 + max(nodeHeight(n.Left), nodeHeight(n.Right))
+}
+
+// avlNode is implemented by the nodes of the AVL trees in this
+// package, so that they share one implementation of the rotations.
+type avlNode[N any] interface {
+	leftChild() N
+	rightChild() N
+	setLeftChild(N)
+	setRightChild(N)
+	// balanceFactor returns the height of the left subtree less
+	// the height of the right subtree.
+	balanceFactor() int
+	// update recomputes the height, and anything else the node
+	// records about its subtree, from its children. It is called
+	// on every node whose children change.
+	update()
+	// mutable returns a node which can be changed in place of this
+	// one: the node itself, or a copy if it may be shared with
+	// another version of the tree.
+	mutable() N
+}
+
+func (n *Node) leftChild() *Node      { return n.Left }
+func (n *Node) rightChild() *Node     { return n.Right }
+func (n *Node) setLeftChild(c *Node)  { n.Left = c }
+func (n *Node) setRightChild(c *Node) { n.Right = c }
+func (n *Node) mutable() *Node        { return n }
+
+func (n *Node) balanceFactor() int {
+	return nodeHeight(n.Left) - nodeHeight(n.Right)
+}
+
+func (n *Node) update() {
+	updateHeight(n)
+	updateSize(n)
+}
+
+// The rotations and rebalance expect n to be mutable already. The
+// child they rotate with may not be, so they ask for a mutable one.
+
+func rotateRight[N avlNode[N]](n N) N {
+	l := n.leftChild().mutable()
+	n.setLeftChild(l.rightChild())
+	l.setRightChild(n)
+	n.update()
+	l.update()
+	return l
+}
+
+func rotateLeft[N avlNode[N]](n N) N {
+	r := n.rightChild().mutable()
+	n.setRightChild(r.leftChild())
+	r.setLeftChild(n)
+	n.update()
+	r.update()
+	return r
+}
+
+// rebalance restores the balance of n after one of its subtrees
+// changed height by one and returns the new root of the subtree.
+func rebalance[N avlNode[N]](n N) N {
+	n.update()
+
+	switch bf := n.balanceFactor(); {
+	case bf > 1:
+		if n.leftChild().balanceFactor() < 0 {
+			n.setLeftChild(rotateLeft(n.leftChild().mutable()))
+		}
+		return rotateRight(n)
+	case bf < -1:
+		if n.rightChild().balanceFactor() > 0 {
+			n.setRightChild(rotateRight(n.rightChild().mutable()))
+		}
+		return rotateLeft(n)
+	}
+	return n
+}
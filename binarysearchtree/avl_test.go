@@ -0,0 +1,204 @@
+package binarysearchtree
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+)
+
+func preOrder(t interface{ PreOrderTraverse(Visitor) }) []string {
+	var result []string
+	t.PreOrderTraverse(func(key int, value interface{}) {
+		result = append(result, fmt.Sprint(key))
+	})
+	return result
+}
+
+func TestAVLRotations(t *testing.T) {
+	tests := map[string]struct {
+		keys     []int
+		deletes  []int
+		expected []string
+	}{
+		"LeftLeft": {
+			[]int{3, 2, 1},
+			nil,
+			[]string{"2", "1", "3"},
+		},
+		"RightRight": {
+			[]int{1, 2, 3},
+			nil,
+			[]string{"2", "1", "3"},
+		},
+		"LeftRight": {
+			[]int{3, 1, 2},
+			nil,
+			[]string{"2", "1", "3"},
+		},
+		"RightLeft": {
+			[]int{1, 3, 2},
+			nil,
+			[]string{"2", "1", "3"},
+		},
+		"DeleteRotatesLeft": {
+			[]int{2, 1, 3, 4},
+			[]int{1},
+			[]string{"3", "2", "4"},
+		},
+		"DeleteRotatesRightLeft": {
+			[]int{2, 1, 4, 3},
+			[]int{1},
+			[]string{"3", "2", "4"},
+		},
+		"DeleteRoot": {
+			[]int{8, 4, 10, 2, 6, 9, 11, 1},
+			[]int{8},
+			[]string{"9", "4", "2", "1", "6", "10", "11"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var tr AVLTree
+			for _, k := range test.keys {
+				tr.Insert(k, k)
+			}
+			for _, k := range test.deletes {
+				if v, ok := tr.Delete(k); !ok || v != k {
+					t.Fatalf("expected %d, got %v", k, v)
+				}
+			}
+
+			if err := tr.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if result := preOrder(&tr); !isSameSlice(result, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestAVLSequential(t *testing.T) {
+	var tr AVLTree
+	for i := 0; i < 1<<16; i++ {
+		tr.Insert(i, i)
+	}
+
+	if err := tr.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if max := int(1.44 * math.Log2(float64(tr.Len()+2))); tr.Height() > max {
+		t.Fatalf("expected height at most %d, got %d", max, tr.Height())
+	}
+	if !tr.Search(1000) || tr.Search(1<<16) {
+		t.Fatal("search not working")
+	}
+	if k, _, _ := tr.Min(); k != 0 {
+		t.Fatalf("expected min 0, got %d", k)
+	}
+	if k, _, _ := tr.Max(); k != 1<<16-1 {
+		t.Fatalf("expected max %d, got %d", 1<<16-1, k)
+	}
+
+	for i := 0; i < 1<<16; i += 2 {
+		tr.Delete(i)
+	}
+	if err := tr.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if tr.Len() != 1<<15 {
+		t.Fatalf("expected %d, got %d", 1<<15, tr.Len())
+	}
+}
+
+func TestAVLValidate(t *testing.T) {
+	var tr AVLTree
+	for _, k := range []int{2, 1, 3} {
+		tr.Insert(k, k)
+	}
+
+	tr.root.Left.Key = 4
+	if tr.Validate() == nil {
+		t.Fatal("expected ordering error")
+	}
+	tr.root.Left.Key = 1
+
+	tr.root.height = 3
+	if tr.Validate() == nil {
+		t.Fatal("expected height error")
+	}
+	tr.root.height = 2
+
+	tr.root.Right = nil
+	tr.root.Left.Left = &Node{Key: 0, height: 1}
+	tr.root.Left.height = 2
+	tr.root.Left.desc = 1
+	tr.root.height = 3
+	tr.size = 3
+	if tr.Validate() == nil {
+		t.Fatal("expected balance error")
+	}
+	tr.root.Right = &Node{Key: 3, height: 1}
+	tr.root.desc = 3
+	tr.size = 4
+	if err := tr.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tr.root.desc = 2
+	if tr.Validate() == nil {
+		t.Fatal("expected size error")
+	}
+}
+
+// FuzzAVL interprets the input as pairs of bytes, inserting the key
+// when the first byte is even and deleting it otherwise, validating
+// the tree against a map after every operation.
+func FuzzAVL(f *testing.F) {
+	f.Add([]byte{0, 8, 0, 4, 0, 10, 0, 2, 0, 6, 0, 1, 0, 3, 0, 5, 0, 7, 0, 9, 0, 11})
+	f.Add([]byte{0, 8, 0, 4, 0, 10, 1, 8, 1, 4, 1, 10})
+	f.Add([]byte{0, 1, 0, 2, 0, 3, 0, 4, 0, 5, 1, 1, 1, 3})
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		var tr AVLTree
+		model := make(map[int]int)
+
+		for i := 0; i+1 < len(ops); i += 2 {
+			key := int(ops[i+1])
+			if ops[i]%2 == 0 {
+				tr.Insert(key, i)
+				model[key] = i
+			} else {
+				v, ok := tr.Delete(key)
+				mv, mok := model[key]
+				if ok != mok || (ok && v != mv) {
+					t.Fatalf("delete %d: expected %v %v, got %v %v", key, mv, mok, v, ok)
+				}
+				delete(model, key)
+			}
+
+			if err := tr.Validate(); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var keys []int
+		for k := range model {
+			keys = append(keys, k)
+		}
+		sort.Ints(keys)
+
+		var got []int
+		tr.InOrderTraverse(func(key int, value interface{}) {
+			if value != model[key] {
+				t.Fatalf("%d: expected %d, got %v", key, model[key], value)
+			}
+			got = append(got, key)
+		})
+		if fmt.Sprint(got) != fmt.Sprint(keys) {
+			t.Fatalf("expected %v, got %v", keys, got)
+		}
+	})
+}
@@ -1,16 +1,27 @@
 package binarysearchtree
 
-import (
-	"errors"
-	"fmt"
-)
+import "errors"
 
 // Node is a leaf and a tree.
+//
+// Each node keeps a count of the nodes below it, which Insert and
+// Remove maintain and Rank, Select, CountRange and Median rely on.
+// A tree built or relinked by hand through Left and Right has stale
+// counts, so call Recount on its root before using those methods.
 type Node struct {
 	Left  *Node
 	Right *Node
 	Key   int
 	Value interface{}
+
+	// desc is the number of nodes below this one. Counting the
+	// descendants rather than the subtree size keeps a leaf
+	// built as a literal correct.
+	desc int
+	// height is only maintained by AVLTree.
+	height int
+	// red is only maintained by RBTree.
+	red bool
 }
 
 // Insert adds the given key and value to the tree.
@@ -18,22 +29,36 @@ func (n *Node) Insert(key int, value interface{}) error {
 	if n == nil {
 		return errors.New("Cannot insert a value into a nil tree")
 	}
+	insert(n, key, value)
+	return nil
+}
+
+// insert adds the key and value below n and returns whether or
+// not a node was added, rather than an existing value replaced.
+func insert(n *Node, key int, value interface{}) bool {
+	added := true
 	switch {
 	case key < n.Key:
 		if n.Left == nil {
 			n.Left = &Node{Key: key, Value: value}
-			return nil
+		} else {
+			added = insert(n.Left, key, value)
 		}
-		return n.Left.Insert(key, value)
 	case key > n.Key:
 		if n.Right == nil {
 			n.Right = &Node{Key: key, Value: value}
-			return nil
+		} else {
+			added = insert(n.Right, key, value)
 		}
-		return n.Right.Insert(key, value)
+	default:
+		n.Value = value
+		return false
 	}
-	n.Value = value
-	return nil
+
+	if added {
+		n.desc++
+	}
+	return added
 }
 
 // Visitor is a function that is called during traversal.
@@ -42,49 +67,27 @@ type Visitor func(key int, value interface{})
 // InOrderTraverse calls Visitor on the left node, the current node,
 // and then the right node.
 func (n *Node) InOrderTraverse(v Visitor) {
-	if n == nil {
-		return
-	}
-
-	if n.Left != nil {
-		n.Left.InOrderTraverse(v)
-	}
-	v(n.Key, n.Value)
-	if n.Right != nil {
-		n.Right.InOrderTraverse(v)
-	}
+	n.InOrderWalk(visitAll(v))
 }
 
 // PreOrderTraverse calls Visitor for the current node, the left node,
 // and the right node.
 func (n *Node) PreOrderTraverse(v Visitor) {
-	if n == nil {
-		return
-	}
-
-	v(n.Key, n.Value)
-	if n.Left != nil {
-		n.Left.PreOrderTraverse(v)
-	}
-	if n.Right != nil {
-		n.Right.PreOrderTraverse(v)
-	}
+	n.PreOrderWalk(visitAll(v))
 }
 
 // PostOrderTraverse calls Visitor for the left node, the right node,
 // and the current node.
 func (n *Node) PostOrderTraverse(v Visitor) {
-	if n == nil {
-		return
-	}
+	n.PostOrderWalk(visitAll(v))
+}
 
-	if n.Left != nil {
-		n.Left.PostOrderTraverse(v)
+// visitAll adapts a Visitor to a WalkFn which never stops.
+func visitAll(v Visitor) WalkFn {
+	return func(key int, value interface{}) bool {
+		v(key, value)
+		return true
 	}
-	if n.Right != nil {
-		n.Right.PostOrderTraverse(v)
-	}
-	v(n.Key, n.Value)
 }
 
 // Min returns the node associated with the min key in the tree.
@@ -165,7 +168,9 @@ func (n *Node) Exact(key int) *Node {
 	return n
 }
 
-// Nearest retrieves the nearest node from the tree for the specified key, or nil.
+// Nearest retrieves the node at which the search for the specified key
+// ends, or nil. It holds the key if it is in the tree, and otherwise
+// the key's floor or ceiling, but not necessarily the closer of them.
 func (n *Node) Nearest(key int) *Node {
 	if n == nil {
 		return nil
@@ -186,7 +191,9 @@ func (n *Node) Nearest(key int) *Node {
 	return n
 }
 
-// Remove removes the node associated with the given key and returns it.
+// Remove removes the node associated with the given key and returns the
+// root of the tree left, which is nil if the tree is now empty. The root
+// changes if the key was the root's and it had fewer than two children.
 func (n *Node) Remove(key int) *Node {
 	return remove(n, key)
 }
@@ -198,11 +205,13 @@ func remove(n *Node, key int) *Node {
 
 	if key < n.Key {
 		n.Left = remove(n.Left, key)
+		updateSize(n)
 		return n
 	}
 
 	if key > n.Key {
 		n.Right = remove(n.Right, key)
+		updateSize(n)
 		return n
 	}
 
@@ -232,30 +241,6 @@ func remove(n *Node, key int) *Node {
 
 	n.Key, n.Value = smallestRight.Key, smallestRight.Value
 	n.Right = remove(n.Right, n.Key)
+	updateSize(n)
 	return n
 }
-
-// String prints a visual representation of the tree
-func (n *Node) String() {
-	fmt.Println("Stringify")
-	stringify(n, 0)
-}
-
-// internal recursive function to print a tree
-func stringify(n *Node, level int) {
-	if n == nil {
-		return
-	}
-	format := ""
-	for i := 0; i < level; i++ {
-		format += "\t"
-	}
-	level++
-	if n.Left != nil {
-		stringify(n.Left, level)
-	}
-	fmt.Printf(format+"%d\n", n.Key)
-	if n.Right != nil {
-		stringify(n.Right, level)
-	}
-}
@@ -2,6 +2,7 @@ package binarysearchtree
 
 import (
 	"fmt"
+	"sort"
 	"testing"
 	"time"
 )
@@ -165,3 +166,169 @@ func TestDates(t *testing.T) {
 		t.Fatalf("expected %f, got %f", 65.5, out.Value)
 	}
 }
+
+// The operations run by FuzzNode. Each is encoded as an op byte,
+// taken modulo numOps, followed by a key byte.
+const (
+	opInsert byte = iota
+	opRemove
+	opExact
+	opMin
+	opMax
+	opNearest
+	numOps
+)
+
+// inserts encodes the insertion of the keys for FuzzNode.
+func inserts(keys ...byte) []byte {
+	var ops []byte
+	for _, k := range keys {
+		ops = append(ops, opInsert, k)
+	}
+	return ops
+}
+
+type modelEntry struct {
+	key   int
+	value interface{}
+}
+
+// sortedModel is the reference FuzzNode checks Node against:
+// a slice of entries kept sorted by key.
+type sortedModel []modelEntry
+
+// search returns the index of the first entry with a key not less than
+// key, and whether that entry holds key.
+func (m sortedModel) search(key int) (int, bool) {
+	i := sort.Search(len(m), func(i int) bool { return m[i].key >= key })
+	return i, i < len(m) && m[i].key == key
+}
+
+func (m sortedModel) insert(key int, value interface{}) sortedModel {
+	i, ok := m.search(key)
+	if ok {
+		m[i].value = value
+		return m
+	}
+	m = append(m, modelEntry{})
+	copy(m[i+1:], m[i:])
+	m[i] = modelEntry{key, value}
+	return m
+}
+
+func (m sortedModel) remove(key int) sortedModel {
+	if i, ok := m.search(key); ok {
+		return append(m[:i], m[i+1:]...)
+	}
+	return m
+}
+
+// checkNode fails unless n and e are both nil or hold the same entry.
+func checkNode(t *testing.T, step string, n *Node, e *modelEntry) {
+	t.Helper()
+	switch {
+	case n == nil && e == nil:
+	case n == nil || e == nil:
+		t.Fatalf("%s: expected %v, got %v", step, e, n)
+	case n.Key != e.key || n.Value != e.value:
+		t.Fatalf("%s: expected %d=%v, got %d=%v", step, e.key, e.value, n.Key, n.Value)
+	}
+}
+
+func FuzzNode(f *testing.F) {
+	// fillTree's keys, built afresh for each seed to append to.
+	full := func() []byte { return inserts(8, 4, 10, 2, 6, 1, 3, 5, 7, 9, 11) }
+	gaps := inserts(80, 40, 100, 20, 60, 10, 30, 50, 70, 90, 110)
+	seeds := [][]byte{
+		// TestRemove.
+		append(full(), opRemove, 1, opMin, 0),
+		// Nodes with two children, including the root.
+		append(full(), opRemove, 4, opRemove, 8, opExact, 5, opRemove, 6, opNearest, 4, opMax, 0),
+		// The root with a single child, and then the last node.
+		append(inserts(1, 2), opRemove, 1, opExact, 2, opRemove, 2, opMin, 0, opInsert, 3, opMax, 0),
+		// TestOrderedQueries.
+		append(gaps, opNearest, 5, opNearest, 55, opNearest, 75, opNearest, 85, opNearest, 120),
+		// Replacing a value and removing a missing key.
+		append(full(), opInsert, 6, opExact, 6, opRemove, 12, opRemove, 0),
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		var root *Node
+		var model sortedModel
+
+		for i := 0; i+1 < len(ops); i += 2 {
+			op, key := ops[i]%numOps, int(ops[i+1])
+			step := fmt.Sprintf("step %d", i/2)
+
+			switch op {
+			case opInsert:
+				if root == nil {
+					root = &Node{Key: key, Value: i}
+				} else {
+					root.Insert(key, i)
+				}
+				model = model.insert(key, i)
+			case opRemove:
+				root = root.Remove(key)
+				model = model.remove(key)
+			case opExact:
+				var e *modelEntry
+				if j, ok := model.search(key); ok {
+					e = &model[j]
+				}
+				checkNode(t, step+": exact", root.Exact(key), e)
+			case opMin:
+				var e *modelEntry
+				if len(model) > 0 {
+					e = &model[0]
+				}
+				checkNode(t, step+": min", root.Min(), e)
+			case opMax:
+				var e *modelEntry
+				if len(model) > 0 {
+					e = &model[len(model)-1]
+				}
+				checkNode(t, step+": max", root.Max(), e)
+			case opNearest:
+				n := root.Nearest(key)
+				j, ok := model.search(key)
+				switch {
+				case len(model) == 0 || ok:
+					var e *modelEntry
+					if ok {
+						e = &model[j]
+					}
+					checkNode(t, step+": nearest", n, e)
+				case n == nil:
+					t.Fatalf("%s: nearest %d: expected a key", step, key)
+				case j > 0 && n.Key == model[j-1].key:
+					checkNode(t, step+": nearest floor", n, &model[j-1])
+				case j < len(model) && n.Key == model[j].key:
+					checkNode(t, step+": nearest ceiling", n, &model[j])
+				default:
+					t.Fatalf("%s: nearest %d: got %d, which is neither its floor nor its ceiling", step, key, n.Key)
+				}
+			}
+
+			if err := root.Validate(); err != nil {
+				t.Fatalf("%s: %v", step, err)
+			}
+			if err := validateSizes(root); err != nil {
+				t.Fatalf("%s: %v", step, err)
+			}
+			if root.Size() != len(model) {
+				t.Fatalf("%s: expected %d keys, got %d", step, len(model), root.Size())
+			}
+			j := 0
+			root.InOrderTraverse(func(key int, value interface{}) {
+				if j >= len(model) || key != model[j].key || value != model[j].value {
+					t.Fatalf("%s: expected %v, got %d=%v at %d", step, model, key, value, j)
+				}
+				j++
+			})
+		}
+	})
+}
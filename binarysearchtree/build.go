@@ -0,0 +1,69 @@
+package binarysearchtree
+
+import "errors"
+
+// BuildBalanced returns a tree of minimum height holding the keys,
+// which must be strictly increasing, in O(n). values may be nil,
+// otherwise it must be the same length as keys.
+func BuildBalanced(keys []int, values []interface{}) (*Node, error) {
+	if values != nil && len(values) != len(keys) {
+		return nil, errors.New("keys and values must be the same length")
+	}
+	for i := 1; i < len(keys); i++ {
+		if keys[i] <= keys[i-1] {
+			return nil, errors.New("keys must be strictly increasing")
+		}
+	}
+
+	nodes := make([]*Node, len(keys))
+	for i, k := range keys {
+		nodes[i] = &Node{Key: k}
+		if values != nil {
+			nodes[i].Value = values[i]
+		}
+	}
+	return link(nodes), nil
+}
+
+// Rebalance rebuilds the tree rooted at n into one of minimum height,
+// reusing its nodes, and returns the new root.
+func (n *Node) Rebalance() *Node {
+	return link(inOrderNodes(n))
+}
+
+// Rebalance rebuilds the tree into one of minimum height.
+func (t *Tree) Rebalance() {
+	t.root = t.root.Rebalance()
+}
+
+// link makes the middle of the sorted nodes the root of the others
+// and returns it. The recursion is only as deep as the new tree.
+func link(nodes []*Node) *Node {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	mid := len(nodes) / 2
+	n := nodes[mid]
+	n.Left = link(nodes[:mid])
+	n.Right = link(nodes[mid+1:])
+	updateSize(n)
+	return n
+}
+
+// inOrderNodes returns the nodes of the tree in ascending order of key.
+func inOrderNodes(n *Node) []*Node {
+	var nodes []*Node
+	var stack []*Node
+	for curr := n; curr != nil || len(stack) > 0; {
+		for curr != nil {
+			stack = append(stack, curr)
+			curr = curr.Left
+		}
+		curr = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		nodes = append(nodes, curr)
+		curr = curr.Right
+	}
+	return nodes
+}
@@ -0,0 +1,112 @@
+package binarysearchtree
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestBuildBalanced(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 7, 8, 1000} {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			keys := make([]int, n)
+			values := make([]interface{}, n)
+			for i := range keys {
+				keys[i] = i * 2
+				values[i] = fmt.Sprint(i * 2)
+			}
+
+			root, err := BuildBalanced(keys, values)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if size := checkSizes(t, root); size != n {
+				t.Fatalf("expected %d nodes, got %d", n, size)
+			}
+			if h, max := root.Height(), int(math.Ceil(math.Log2(float64(n+1)))); h != max {
+				t.Fatalf("expected height %d, got %d", max, h)
+			}
+
+			i := 0
+			root.InOrderTraverse(func(key int, value interface{}) {
+				if key != keys[i] || value != values[i] {
+					t.Fatalf("%d: expected %d %v, got %d %v", i, keys[i], values[i], key, value)
+				}
+				i++
+			})
+		})
+	}
+}
+
+func TestBuildBalancedInvalid(t *testing.T) {
+	if _, err := BuildBalanced([]int{1, 2}, []interface{}{1}); err == nil {
+		t.Fatal("expected a length error")
+	}
+	if _, err := BuildBalanced([]int{1, 1}, nil); err == nil {
+		t.Fatal("expected an ordering error")
+	}
+
+	root, err := BuildBalanced([]int{1, 2, 3}, nil)
+	if err != nil || root.Key != 2 || root.Value != nil {
+		t.Fatalf("expected root 2 without a value, got %v %v", root, err)
+	}
+}
+
+func TestRebalance(t *testing.T) {
+	var tr Tree
+	for i := 0; i < 1000; i++ {
+		tr.Insert(i, i)
+	}
+	if tr.Height() != 1000 {
+		t.Fatalf("expected a degenerate tree, got height %d", tr.Height())
+	}
+
+	tr.Rebalance()
+	if tr.Height() != 10 {
+		t.Fatalf("expected height 10, got %d", tr.Height())
+	}
+	if tr.Len() != 1000 {
+		t.Fatalf("expected 1000 keys, got %d", tr.Len())
+	}
+	checkSizes(t, tr.root)
+	for i := 0; i < 1000; i++ {
+		if v, ok := tr.Get(i); !ok || v != i {
+			t.Fatalf("expected %d, got %v", i, v)
+		}
+	}
+
+	// The rebuilt tree should stay usable.
+	tr.Delete(500)
+	tr.Insert(2000, 2000)
+	checkSizes(t, tr.root)
+	if r := tr.Rank(2000); r != 999 {
+		t.Fatalf("expected rank 999, got %d", r)
+	}
+
+	var empty Tree
+	empty.Rebalance()
+	if empty.Len() != 0 {
+		t.Fatal("expected an empty tree")
+	}
+}
+
+func BenchmarkBuild(b *testing.B) {
+	keys := make([]int, 1<<16)
+	for i := range keys {
+		keys[i] = i
+	}
+
+	b.Run("Balanced", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			BuildBalanced(keys, nil)
+		}
+	})
+	b.Run("AVLInsert", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var tr AVLTree
+			for _, k := range keys {
+				tr.Insert(k, nil)
+			}
+		}
+	})
+}
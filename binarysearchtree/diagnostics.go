@@ -0,0 +1,217 @@
+package binarysearchtree
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// The checks below walk the tree with their own stacks, like the walks
+// in walk.go, so they are safe to use on trees of any shape, including
+// the degenerate ones they are most likely to be asked about.
+
+// Validate returns an error if a key is out of order. It only looks
+// at the keys, so it holds for a tree built or relinked by hand as
+// well as one built by Insert, and it doesn't modify the tree, so it
+// can be used to assert the tree is sound after any operation,
+// including Remove.
+func (n *Node) Validate() error {
+	type bounded struct {
+		node   *Node
+		lo, hi *int
+	}
+
+	if n == nil {
+		return nil
+	}
+
+	stack := []bounded{{node: n}}
+	for len(stack) > 0 {
+		b := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		curr := b.node
+		if (b.lo != nil && curr.Key <= *b.lo) || (b.hi != nil && curr.Key >= *b.hi) {
+			return fmt.Errorf("key %d is out of order", curr.Key)
+		}
+
+		if curr.Right != nil {
+			stack = append(stack, bounded{curr.Right, &curr.Key, b.hi})
+		}
+		if curr.Left != nil {
+			stack = append(stack, bounded{curr.Left, b.lo, &curr.Key})
+		}
+	}
+	return nil
+}
+
+// validateSizes returns an error if a node's stored count of its
+// descendants, which Rank and Select rely on, is wrong.
+func validateSizes(n *Node) error {
+	var err error
+	postOrder(n, func(curr *Node) bool {
+		// The children's counts are checked first, so checking each
+		// node against them alone is enough to check the whole tree.
+		if desc := size(curr.Left) + size(curr.Right); curr.desc != desc {
+			err = fmt.Errorf("key %d has %d descendants, expected %d", curr.Key, curr.desc, desc)
+			return false
+		}
+		return true
+	})
+	return err
+}
+
+// Size returns the number of nodes in the tree, zero for a nil tree.
+// It counts them, so unlike Rank and Select it doesn't rely on the
+// tree having been built by Insert.
+func (n *Node) Size() int {
+	count := 0
+	postOrder(n, func(*Node) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// Height returns the number of nodes on the longest path from the
+// root to a leaf, zero for a nil tree.
+func (n *Node) Height() int {
+	return subtreeHeights(n, func(*Node, int, int) bool { return true })
+}
+
+// BalanceFactor returns the height of the left subtree less the
+// height of the right subtree.
+func (n *Node) BalanceFactor() int {
+	if n == nil {
+		return 0
+	}
+	return n.Left.Height() - n.Right.Height()
+}
+
+// IsBalanced returns true if the subtrees of every node differ in
+// height by at most one, as in an AVL tree.
+func (n *Node) IsBalanced() bool {
+	balanced := true
+	subtreeHeights(n, func(_ *Node, l, r int) bool {
+		balanced = l-r <= 1 && r-l <= 1
+		return balanced
+	})
+	return balanced
+}
+
+// subtreeHeights calls fn for each node after its children, with the
+// heights of its left and right subtrees, until it returns false. It
+// returns the height of the tree, or zero if fn stopped early.
+func subtreeHeights(n *Node, fn func(n *Node, l, r int) bool) int {
+	// The heights of the subtrees visited but not yet claimed by
+	// their parent. A node's children are visited just before it,
+	// so their heights are on the top of the stack.
+	var heights []int
+	pop := func() int {
+		h := heights[len(heights)-1]
+		heights = heights[:len(heights)-1]
+		return h
+	}
+
+	stopped := false
+	postOrder(n, func(curr *Node) bool {
+		var l, r int
+		if curr.Right != nil {
+			r = pop()
+		}
+		if curr.Left != nil {
+			l = pop()
+		}
+
+		if !fn(curr, l, r) {
+			stopped = true
+			return false
+		}
+		heights = append(heights, 1+max(l, r))
+		return true
+	})
+
+	if stopped || len(heights) == 0 {
+		return 0
+	}
+	return heights[0]
+}
+
+// DepthDistribution returns the number of nodes at each depth of the
+// tree, starting with the root at depth zero. It is empty for a nil
+// tree, and its length is the height of the tree.
+func (n *Node) DepthDistribution() []int {
+	var counts []int
+	n.LevelOrderTraverse(func(key int, value interface{}, depth int) bool {
+		if depth == len(counts) {
+			counts = append(counts, 0)
+		}
+		counts[depth]++
+		return true
+	})
+	return counts
+}
+
+// Equal returns true if the trees have the same shape and the same
+// keys and values in each position. Values are compared with
+// reflect.DeepEqual.
+func (n *Node) Equal(o *Node) bool {
+	type pair struct{ a, b *Node }
+
+	stack := []pair{{n, o}}
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if p.a == nil || p.b == nil {
+			if p.a != p.b {
+				return false
+			}
+			continue
+		}
+		if p.a.Key != p.b.Key || !reflect.DeepEqual(p.a.Value, p.b.Value) {
+			return false
+		}
+		stack = append(stack, pair{p.a.Right, p.b.Right}, pair{p.a.Left, p.b.Left})
+	}
+	return true
+}
+
+// SameContents returns true if the trees hold the same keys and
+// values, whatever their shapes. Values are compared with
+// reflect.DeepEqual.
+func (n *Node) SameContents(o *Node) bool {
+	a, b := n.Iterator(), o.Iterator()
+	for okA, okB := a.First(), b.First(); okA || okB; okA, okB = a.Next(), b.Next() {
+		if okA != okB || a.Key() != b.Key() || !reflect.DeepEqual(a.Value(), b.Value()) {
+			return false
+		}
+	}
+	return true
+}
+
+// SameContents returns true if the maps hold the same keys and values,
+// so that, for example, an AVLTree can be checked against a Tree built
+// from the same operations. Values are compared with reflect.DeepEqual.
+func SameContents(a, b OrderedMap) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+
+	type kv struct {
+		key   int
+		value interface{}
+	}
+	entries := make([]kv, 0, a.Len())
+	a.InOrderTraverse(func(key int, value interface{}) {
+		entries = append(entries, kv{key, value})
+	})
+
+	i, same := 0, true
+	b.InOrderTraverse(func(key int, value interface{}) {
+		if i >= len(entries) || entries[i].key != key || !reflect.DeepEqual(entries[i].value, value) {
+			same = false
+		}
+		i++
+	})
+	return same && i == len(entries)
+}
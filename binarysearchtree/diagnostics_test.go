@@ -0,0 +1,235 @@
+package binarysearchtree
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// build returns a tree with the keys inserted in order.
+func build(keys ...int) *Node {
+	root := &Node{Key: keys[0], Value: keys[0]}
+	for _, k := range keys[1:] {
+		root.Insert(k, k)
+	}
+	return root
+}
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		root  func() *Node
+		valid bool
+	}{
+		"Nil":      {func() *Node { return nil }, true},
+		"Leaf":     {func() *Node { return &Node{Key: 1} }, true},
+		"Inserted": {gapTree, true},
+		"LeftOfParent": {func() *Node {
+			root := gapTree()
+			root.Left.Left.Key = 45
+			return root
+		}, false},
+		"RightOfAncestor": {func() *Node {
+			// 85 is greater than its parent 60, but it is in the
+			// root's left subtree so must be less than 80.
+			root := gapTree()
+			root.Left.Right.Right.Key = 85
+			return root
+		}, false},
+		"Duplicate": {func() *Node {
+			root := gapTree()
+			root.Right.Left.Key = 80
+			return root
+		}, false},
+		"Literal": {func() *Node { return chain(3, false) }, true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := test.root().Validate(); (err == nil) != test.valid {
+				t.Fatalf("expected valid %v, got %v", test.valid, err)
+			}
+		})
+	}
+}
+
+func TestValidateSizes(t *testing.T) {
+	tests := map[string]struct {
+		root  func() *Node
+		valid bool
+	}{
+		"Nil":      {func() *Node { return nil }, true},
+		"Inserted": {gapTree, true},
+		"WrongCount": {func() *Node {
+			root := gapTree()
+			root.Right.desc = 1
+			return root
+		}, false},
+		"Literal": {func() *Node { return chain(3, false) }, false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := validateSizes(test.root()); (err == nil) != test.valid {
+				t.Fatalf("expected valid %v, got %v", test.valid, err)
+			}
+		})
+	}
+}
+
+func TestSize(t *testing.T) {
+	tests := map[string]struct {
+		root *Node
+		size int
+	}{
+		"Nil":      {nil, 0},
+		"Inserted": {gapTree(), 11},
+		"Literal":  {&Node{Key: 2, Left: &Node{Key: 1}, Right: &Node{Key: 3}}, 3},
+		"Chain":    {chain(50, true), 50},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if s := test.root.Size(); s != test.size {
+				t.Fatalf("expected %d, got %d", test.size, s)
+			}
+		})
+	}
+}
+
+func TestValidateAfterRemove(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	var tr Tree
+	for i := 0; i < 2000; i++ {
+		key := r.Intn(200)
+		if r.Intn(3) == 0 {
+			tr.Delete(key)
+		} else {
+			tr.Insert(key, i)
+		}
+
+		if err := tr.Validate(); err != nil {
+			t.Fatalf("after %d operations: %v", i+1, err)
+		}
+	}
+
+	tr.size++
+	if err := tr.Validate(); err == nil {
+		t.Fatal("expected a count error")
+	}
+}
+
+func TestShape(t *testing.T) {
+	tests := map[string]struct {
+		root          *Node
+		height        int
+		balanceFactor int
+		balanced      bool
+		depths        []int
+	}{
+		"Nil":       {nil, 0, 0, true, nil},
+		"Leaf":      {&Node{Key: 1}, 1, 0, true, []int{1}},
+		"Full":      {gapTree(), 4, 1, true, []int{1, 2, 4, 4}},
+		"Ascending": {build(1, 2, 3), 3, -2, false, []int{1, 1, 1}},
+		"Legs":      {build(5, 3, 8, 2, 9, 1, 10), 4, 0, false, []int{1, 2, 2, 2}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if h := test.root.Height(); h != test.height {
+				t.Fatalf("expected height %d, got %d", test.height, h)
+			}
+			if bf := test.root.BalanceFactor(); bf != test.balanceFactor {
+				t.Fatalf("expected balance factor %d, got %d", test.balanceFactor, bf)
+			}
+			if b := test.root.IsBalanced(); b != test.balanced {
+				t.Fatalf("expected balanced %v, got %v", test.balanced, b)
+			}
+			if d := test.root.DepthDistribution(); fmt.Sprint(d) != fmt.Sprint(test.depths) {
+				t.Fatalf("expected depths %v, got %v", test.depths, d)
+			}
+		})
+	}
+}
+
+func TestShapeDegenerate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping deep trees in short mode")
+	}
+
+	const n = 1 << 20
+	root := chain(n, true)
+	if h := root.Height(); h != n {
+		t.Fatalf("expected height %d, got %d", n, h)
+	}
+	if root.IsBalanced() {
+		t.Fatal("expected a chain to be unbalanced")
+	}
+	if !root.Equal(chain(n, true)) {
+		t.Fatal("expected equal chains")
+	}
+}
+
+func TestEqual(t *testing.T) {
+	tests := map[string]struct {
+		a, b        *Node
+		equal, same bool
+	}{
+		"Nil":        {nil, nil, true, true},
+		"NilAndLeaf": {nil, &Node{Key: 1}, false, false},
+		"Same":       {build(2, 1, 3), build(2, 1, 3), true, true},
+		"Reshaped":   {build(2, 1, 3), build(1, 2, 3), false, true},
+		"Missing":    {build(2, 1, 3), build(2, 1), false, false},
+		"Extra":      {build(2, 1), build(2, 1, 3), false, false},
+		"Value": {build(2, 1, 3), func() *Node {
+			n := build(2, 1, 3)
+			n.Right.Value = "3"
+			return n
+		}(), false, false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if eq := test.a.Equal(test.b); eq != test.equal {
+				t.Fatalf("expected equal %v, got %v", test.equal, eq)
+			}
+			if same := test.a.SameContents(test.b); same != test.same {
+				t.Fatalf("expected same contents %v, got %v", test.same, same)
+			}
+		})
+	}
+}
+
+func TestSameContentsMaps(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	var maps []OrderedMap
+	for _, m := range orderedMaps {
+		maps = append(maps, m.new())
+	}
+	for i := 0; i < 500; i++ {
+		key, del := r.Intn(100), r.Intn(3) == 0
+		for _, m := range maps {
+			if del {
+				m.Delete(key)
+			} else {
+				m.Insert(key, []int{i})
+			}
+		}
+	}
+
+	for _, m := range maps[1:] {
+		if !SameContents(maps[0], m) {
+			t.Fatal("expected the same contents")
+		}
+	}
+
+	maps[0].Insert(1000, nil)
+	if SameContents(maps[0], maps[1]) || SameContents(maps[1], maps[0]) {
+		t.Fatal("expected different contents")
+	}
+	maps[1].Insert(1000, 0)
+	if SameContents(maps[0], maps[1]) {
+		t.Fatal("expected different values")
+	}
+}
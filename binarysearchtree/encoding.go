@@ -0,0 +1,232 @@
+package binarysearchtree
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+)
+
+var errCorrupt = errors.New("corrupt tree encoding")
+
+// ValueEncoder converts a value to bytes for EncodeBinary.
+type ValueEncoder func(v interface{}) ([]byte, error)
+
+// ValueDecoder converts bytes written by a ValueEncoder back to a value.
+type ValueDecoder func(data []byte) (interface{}, error)
+
+// encodedNode is one node of a preorder encoding. Recording which
+// children a node has is enough to rebuild the exact shape.
+type encodedNode struct {
+	Key   int         `json:"key"`
+	Value interface{} `json:"value,omitempty"`
+	Left  bool        `json:"left,omitempty"`
+	Right bool        `json:"right,omitempty"`
+}
+
+const (
+	hasLeft = 1 << iota
+	hasRight
+	hasValue
+)
+
+// EncodeBinary encodes the tree in preorder, so that DecodeBinary
+// rebuilds exactly the same shape. Each value is encoded with enc,
+// or left out if enc is nil.
+func EncodeBinary(n *Node, enc ValueEncoder) ([]byte, error) {
+	nodes := flatten(n)
+
+	out := make([]byte, binary.MaxVarintLen64)
+	out = out[:binary.PutUvarint(out, uint64(len(nodes)))]
+	buf := make([]byte, binary.MaxVarintLen64)
+	for _, e := range nodes {
+		var tag byte
+		if e.Left {
+			tag |= hasLeft
+		}
+		if e.Right {
+			tag |= hasRight
+		}
+
+		var value []byte
+		if enc != nil {
+			var err error
+			if value, err = enc(e.Value); err != nil {
+				return nil, err
+			}
+			tag |= hasValue
+		}
+
+		out = append(out, tag)
+		out = append(out, buf[:binary.PutVarint(buf, int64(e.Key))]...)
+		if tag&hasValue != 0 {
+			out = append(out, buf[:binary.PutUvarint(buf, uint64(len(value)))]...)
+			out = append(out, value...)
+		}
+	}
+	return out, nil
+}
+
+// DecodeBinary rebuilds a tree encoded by EncodeBinary, decoding
+// each value with dec. Values are nil if they weren't encoded.
+func DecodeBinary(data []byte, dec ValueDecoder) (*Node, error) {
+	count, read := binary.Uvarint(data)
+	// Every node takes at least two bytes.
+	if read <= 0 || count > uint64(len(data)/2) {
+		return nil, errCorrupt
+	}
+	data = data[read:]
+
+	nodes := make([]encodedNode, count)
+	for i := range nodes {
+		if len(data) == 0 {
+			return nil, errCorrupt
+		}
+		tag := data[0]
+		data = data[1:]
+		if tag&^(hasLeft|hasRight|hasValue) != 0 {
+			return nil, errCorrupt
+		}
+
+		key, read := binary.Varint(data)
+		if read <= 0 {
+			return nil, errCorrupt
+		}
+		data = data[read:]
+		nodes[i] = encodedNode{Key: int(key), Left: tag&hasLeft != 0, Right: tag&hasRight != 0}
+
+		if tag&hasValue == 0 {
+			continue
+		}
+		length, read := binary.Uvarint(data)
+		if read <= 0 || length > uint64(len(data)-read) {
+			return nil, errCorrupt
+		}
+		value := data[read : read+int(length)]
+		data = data[read+int(length):]
+		if dec != nil {
+			v, err := dec(value)
+			if err != nil {
+				return nil, err
+			}
+			nodes[i].Value = v
+		}
+	}
+	if len(data) != 0 {
+		return nil, errCorrupt
+	}
+
+	return unflatten(nodes)
+}
+
+// EncodeJSON encodes the tree as a JSON array of nodes in preorder,
+// so that DecodeJSON rebuilds exactly the same shape.
+func EncodeJSON(n *Node) ([]byte, error) {
+	nodes := flatten(n)
+	if nodes == nil {
+		nodes = []encodedNode{}
+	}
+	return json.Marshal(nodes)
+}
+
+// DecodeJSON rebuilds a tree encoded by EncodeJSON. The values are
+// decoded as encoding/json decodes into an interface{}, so numbers
+// come back as float64.
+func DecodeJSON(data []byte) (*Node, error) {
+	var nodes []encodedNode
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		return nil, err
+	}
+	return unflatten(nodes)
+}
+
+// MarshalJSON encodes the tree with EncodeJSON.
+func (t *Tree) MarshalJSON() ([]byte, error) {
+	return EncodeJSON(t.root)
+}
+
+// UnmarshalJSON replaces the tree with one encoded by MarshalJSON.
+func (t *Tree) UnmarshalJSON(data []byte) error {
+	root, err := DecodeJSON(data)
+	if err != nil {
+		return err
+	}
+	t.root, t.size = root, size(root)
+	return nil
+}
+
+// flatten returns the nodes below n in preorder.
+func flatten(n *Node) []encodedNode {
+	if n == nil {
+		return nil
+	}
+
+	var nodes []encodedNode
+	stack := []*Node{n}
+	for len(stack) > 0 {
+		curr := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		nodes = append(nodes, encodedNode{
+			Key:   curr.Key,
+			Value: curr.Value,
+			Left:  curr.Left != nil,
+			Right: curr.Right != nil,
+		})
+
+		if curr.Right != nil {
+			stack = append(stack, curr.Right)
+		}
+		if curr.Left != nil {
+			stack = append(stack, curr.Left)
+		}
+	}
+	return nodes
+}
+
+// unflatten rebuilds the tree from its preorder nodes, checking
+// that the nodes describe exactly one correctly ordered tree.
+func unflatten(nodes []encodedNode) (*Node, error) {
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+
+	// slot is a child pointer waiting to be filled, along
+	// with the bounds on the keys allowed beneath it.
+	type slot struct {
+		child  **Node
+		lo, hi *int
+	}
+
+	var root *Node
+	stack := []slot{{child: &root}}
+	built := make([]*Node, 0, len(nodes))
+	for _, e := range nodes {
+		if len(stack) == 0 {
+			return nil, errCorrupt
+		}
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if (s.lo != nil && e.Key <= *s.lo) || (s.hi != nil && e.Key >= *s.hi) {
+			return nil, errCorrupt
+		}
+
+		n := &Node{Key: e.Key, Value: e.Value}
+		*s.child = n
+		built = append(built, n)
+		if e.Right {
+			stack = append(stack, slot{&n.Right, &n.Key, s.hi})
+		}
+		if e.Left {
+			stack = append(stack, slot{&n.Left, s.lo, &n.Key})
+		}
+	}
+	if len(stack) != 0 {
+		return nil, errCorrupt
+	}
+
+	// Every node comes after its ancestors in preorder,
+	// so sizes can be filled in backwards.
+	for i := len(built) - 1; i >= 0; i-- {
+		updateSize(built[i])
+	}
+	return root, nil
+}
@@ -0,0 +1,170 @@
+package binarysearchtree
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func stringEncoder(v interface{}) ([]byte, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, errors.New("not a string")
+	}
+	return []byte(s), nil
+}
+
+func stringDecoder(data []byte) (interface{}, error) {
+	return string(data), nil
+}
+
+func encodingTrees() map[string]*Node {
+	full := &Node{Key: 8, Value: "8"}
+	fillTree(full)
+
+	lopsided := &Node{Key: 50, Value: "50"}
+	for _, k := range []int{20, 10, 15, 70, 90, 80, 100, 1000, -5, math.MinInt, math.MaxInt} {
+		lopsided.Insert(k, fmt.Sprint(k))
+	}
+
+	deep := chain(1000, false)
+	for n := deep; n != nil; n = n.Right {
+		n.Value = fmt.Sprint(n.Key)
+	}
+
+	return map[string]*Node{
+		"Empty":    nil,
+		"Single":   {Key: 1, Value: "1"},
+		"Full":     full,
+		"Lopsided": lopsided,
+		"Chain":    deep,
+	}
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	for name, root := range encodingTrees() {
+		t.Run(name, func(t *testing.T) {
+			data, err := EncodeBinary(root, stringEncoder)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := DecodeBinary(data, stringDecoder)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !root.Equal(decoded) {
+				t.Fatalf("expected %v, got %v", root, decoded)
+			}
+			checkSizes(t, decoded)
+		})
+	}
+}
+
+func TestBinaryWithoutValues(t *testing.T) {
+	root := &Node{Key: 8, Value: "8"}
+	fillTree(root)
+
+	data, err := EncodeBinary(root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeBinary(data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preorder := fmt.Sprint(preOrder(decoded)); preorder != fmt.Sprint(preOrder(root)) {
+		t.Fatalf("expected %v, got %s", preOrder(root), preorder)
+	}
+	if decoded.Value != nil {
+		t.Fatalf("expected no value, got %v", decoded.Value)
+	}
+
+	if _, err := EncodeBinary(&Node{Key: 1, Value: 1}, stringEncoder); err == nil {
+		t.Fatal("expected the encoder's error")
+	}
+}
+
+func TestBinaryCorrupt(t *testing.T) {
+	root := &Node{Key: 8, Value: "8"}
+	fillTree(root)
+	data, _ := EncodeBinary(root, stringEncoder)
+
+	tests := map[string][]byte{
+		"Empty":     {},
+		"Truncated": data[:len(data)-1],
+		"Trailing":  append(append([]byte{}, data...), 0),
+		"BadTag":    append([]byte{data[0], 0xff}, data[2:]...),
+		"TooMany":   append([]byte{data[0] + 1}, data[1:]...),
+		"TooFew":    append([]byte{data[0] - 1}, data[1:]...),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := DecodeBinary(data, stringDecoder); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	for name, root := range encodingTrees() {
+		t.Run(name, func(t *testing.T) {
+			data, err := EncodeJSON(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := DecodeJSON(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !root.Equal(decoded) {
+				t.Fatalf("expected %v, got %v", root, decoded)
+			}
+		})
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	root := &Node{Key: 2, Value: "b"}
+	root.Insert(1, "a")
+
+	data, err := EncodeJSON(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `[{"key":2,"value":"b","left":true},{"key":1,"value":"a"}]`; string(data) != expected {
+		t.Fatalf("expected %s, got %s", expected, data)
+	}
+
+	for _, bad := range []string{
+		`[{"key":2,"left":true}]`,
+		`[{"key":2},{"key":1}]`,
+		`[{"key":2,"left":true},{"key":3}]`,
+	} {
+		if _, err := DecodeJSON([]byte(bad)); err == nil {
+			t.Fatalf("expected an error decoding %s", bad)
+		}
+	}
+}
+
+func TestTreeJSON(t *testing.T) {
+	var tr Tree
+	for _, k := range []int{5, 3, 8, 1} {
+		tr.Insert(k, fmt.Sprint(k))
+	}
+
+	data, err := json.Marshal(&tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Tree
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Len() != 4 || !tr.root.Equal(decoded.root) {
+		t.Fatalf("expected %v, got %v", tr.root, decoded.root)
+	}
+}
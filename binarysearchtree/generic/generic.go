@@ -0,0 +1,236 @@
+package generic
+
+import "cmp"
+
+// Node is a leaf of a Tree.
+type Node[K, V any] struct {
+	Left  *Node[K, V]
+	Right *Node[K, V]
+	Key   K
+	Value V
+}
+
+// Visitor is a function that is called during traversal.
+type Visitor[K, V any] func(key K, value V)
+
+// Tree is a binary search tree ordered by a compare function,
+// which returns a negative number when a < b, a positive number
+// when a > b and zero when they are equal.
+type Tree[K, V any] struct {
+	root    *Node[K, V]
+	compare func(a, b K) int
+	size    int
+}
+
+// New creates a new, empty, tree for ordered keys.
+func New[K cmp.Ordered, V any]() *Tree[K, V] {
+	return NewFunc[K, V](cmp.Compare[K])
+}
+
+// NewFunc creates a new, empty, tree ordered by the compare function.
+func NewFunc[K, V any](compare func(a, b K) int) *Tree[K, V] {
+	return &Tree[K, V]{compare: compare}
+}
+
+// Len returns the number of keys in the tree.
+func (t *Tree[K, V]) Len() int {
+	return t.size
+}
+
+// Insert adds the given key and value to the tree,
+// replacing the value if the key already exists.
+func (t *Tree[K, V]) Insert(key K, value V) {
+	if t.root == nil {
+		t.root = &Node[K, V]{Key: key, Value: value}
+		t.size++
+		return
+	}
+
+	n := t.root
+	for {
+		c := t.compare(key, n.Key)
+		switch {
+		case c < 0:
+			if n.Left == nil {
+				n.Left = &Node[K, V]{Key: key, Value: value}
+				t.size++
+				return
+			}
+			n = n.Left
+		case c > 0:
+			if n.Right == nil {
+				n.Right = &Node[K, V]{Key: key, Value: value}
+				t.size++
+				return
+			}
+			n = n.Right
+		default:
+			n.Value = value
+			return
+		}
+	}
+}
+
+// InOrderTraverse calls Visitor on the left node, the current node,
+// and then the right node.
+func (t *Tree[K, V]) InOrderTraverse(v Visitor[K, V]) {
+	inOrder(t.root, v)
+}
+
+func inOrder[K, V any](n *Node[K, V], v Visitor[K, V]) {
+	if n == nil {
+		return
+	}
+
+	inOrder(n.Left, v)
+	v(n.Key, n.Value)
+	inOrder(n.Right, v)
+}
+
+// PreOrderTraverse calls Visitor for the current node, the left node,
+// and the right node.
+func (t *Tree[K, V]) PreOrderTraverse(v Visitor[K, V]) {
+	preOrder(t.root, v)
+}
+
+func preOrder[K, V any](n *Node[K, V], v Visitor[K, V]) {
+	if n == nil {
+		return
+	}
+
+	v(n.Key, n.Value)
+	preOrder(n.Left, v)
+	preOrder(n.Right, v)
+}
+
+// PostOrderTraverse calls Visitor for the left node, the right node,
+// and the current node.
+func (t *Tree[K, V]) PostOrderTraverse(v Visitor[K, V]) {
+	postOrder(t.root, v)
+}
+
+func postOrder[K, V any](n *Node[K, V], v Visitor[K, V]) {
+	if n == nil {
+		return
+	}
+
+	postOrder(n.Left, v)
+	postOrder(n.Right, v)
+	v(n.Key, n.Value)
+}
+
+// Min returns the node associated with the min key in the tree.
+func (t *Tree[K, V]) Min() *Node[K, V] {
+	if t.root == nil {
+		return nil
+	}
+
+	curr := t.root
+	for curr.Left != nil {
+		curr = curr.Left
+	}
+	return curr
+}
+
+// Max returns the node associated with the max key in the tree.
+func (t *Tree[K, V]) Max() *Node[K, V] {
+	if t.root == nil {
+		return nil
+	}
+
+	curr := t.root
+	for curr.Right != nil {
+		curr = curr.Right
+	}
+	return curr
+}
+
+// Search returns true if the given key is found within the tree.
+func (t *Tree[K, V]) Search(key K) bool {
+	return t.Exact(key) != nil
+}
+
+// Exact retrieves a node from the tree for the specified key, or nil.
+func (t *Tree[K, V]) Exact(key K) *Node[K, V] {
+	n := t.root
+	for n != nil {
+		c := t.compare(key, n.Key)
+		switch {
+		case c < 0:
+			n = n.Left
+		case c > 0:
+			n = n.Right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// Nearest retrieves the node at which the search for the
+// specified key ends, or nil if the tree is empty.
+func (t *Tree[K, V]) Nearest(key K) *Node[K, V] {
+	n := t.root
+	for n != nil {
+		c := t.compare(key, n.Key)
+		switch {
+		case c < 0 && n.Left != nil:
+			n = n.Left
+		case c > 0 && n.Right != nil:
+			n = n.Right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// Remove removes the node associated with the given key and
+// returns its value.
+func (t *Tree[K, V]) Remove(key K) (V, bool) {
+	n := t.Exact(key)
+	if n == nil {
+		var zero V
+		return zero, false
+	}
+
+	// remove may copy another node into n, so take the value first.
+	value := n.Value
+	t.root = t.remove(t.root, key)
+	t.size--
+	return value, true
+}
+
+func (t *Tree[K, V]) remove(n *Node[K, V], key K) *Node[K, V] {
+	if n == nil {
+		return nil
+	}
+
+	c := t.compare(key, n.Key)
+	if c < 0 {
+		n.Left = t.remove(n.Left, key)
+		return n
+	}
+
+	if c > 0 {
+		n.Right = t.remove(n.Right, key)
+		return n
+	}
+
+	if n.Left == nil {
+		return n.Right
+	}
+
+	if n.Right == nil {
+		return n.Left
+	}
+
+	smallestRight := n.Right
+	for smallestRight.Left != nil {
+		smallestRight = smallestRight.Left
+	}
+
+	n.Key, n.Value = smallestRight.Key, smallestRight.Value
+	n.Right = t.remove(n.Right, n.Key)
+	return n
+}
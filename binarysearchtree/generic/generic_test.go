@@ -0,0 +1,229 @@
+package generic
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func fillTree(bst *Tree[int, string]) {
+	for _, k := range []int{8, 4, 10, 2, 6, 1, 3, 5, 7, 9, 11} {
+		bst.Insert(k, fmt.Sprint(k))
+	}
+}
+
+// isSameSlice returns true if the 2 slices are identical
+func isSameSlice(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTraverse(t *testing.T) {
+	tests := map[string]struct {
+		traverse func(*Tree[int, string], Visitor[int, string])
+		expected []string
+	}{
+		"InOrder": {
+			(*Tree[int, string]).InOrderTraverse,
+			[]string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"},
+		},
+		"PreOrder": {
+			(*Tree[int, string]).PreOrderTraverse,
+			[]string{"8", "4", "2", "1", "3", "6", "5", "7", "10", "9", "11"},
+		},
+		"PostOrder": {
+			(*Tree[int, string]).PostOrderTraverse,
+			[]string{"1", "3", "2", "5", "7", "6", "4", "9", "11", "10", "8"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			bst := New[int, string]()
+			fillTree(bst)
+
+			var result []string
+			test.traverse(bst, func(key int, value string) {
+				result = append(result, value)
+			})
+			if !isSameSlice(result, test.expected) {
+				t.Errorf("Traversal order incorrect, got %v instead of %v", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestMin(t *testing.T) {
+	bst := New[int, string]()
+	if bst.Min() != nil {
+		t.Errorf("min of an empty tree should be nil")
+	}
+
+	fillTree(bst)
+	if bst.Min().Value != "1" {
+		t.Errorf("min should be 1")
+	}
+}
+
+func TestMax(t *testing.T) {
+	bst := New[int, string]()
+	if bst.Max() != nil {
+		t.Errorf("max of an empty tree should be nil")
+	}
+
+	fillTree(bst)
+	if bst.Max().Value != "11" {
+		t.Errorf("max should be 11")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	bst := New[int, string]()
+	fillTree(bst)
+
+	if !bst.Search(1) || !bst.Search(8) || !bst.Search(11) || bst.Search(12) {
+		t.Errorf("search not working")
+	}
+	if bst.Exact(6).Value != "6" || bst.Exact(0) != nil {
+		t.Errorf("exact not working")
+	}
+}
+
+func TestRemove(t *testing.T) {
+	tests := map[string]struct {
+		removes  []int
+		expected []string
+	}{
+		"Leaf":     {[]int{1}, []string{"2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}},
+		"TwoChild": {[]int{4}, []string{"1", "2", "3", "5", "6", "7", "8", "9", "10", "11"}},
+		"Root":     {[]int{8}, []string{"1", "2", "3", "4", "5", "6", "7", "9", "10", "11"}},
+		"Missing":  {[]int{12}, []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}},
+		"All":      {[]int{8, 4, 10, 2, 6, 1, 3, 5, 7, 9, 11}, nil},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			bst := New[int, string]()
+			fillTree(bst)
+
+			for _, k := range test.removes {
+				v, ok := bst.Remove(k)
+				if ok && v != fmt.Sprint(k) {
+					t.Fatalf("expected %d, got %s", k, v)
+				}
+			}
+
+			var result []string
+			bst.InOrderTraverse(func(key int, value string) {
+				result = append(result, value)
+			})
+			if !isSameSlice(result, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, result)
+			}
+			if bst.Len() != len(test.expected) {
+				t.Fatalf("expected len %d, got %d", len(test.expected), bst.Len())
+			}
+		})
+	}
+}
+
+func TestStrings(t *testing.T) {
+	bst := New[string, int]()
+	for i, k := range []string{"m", "c", "x", "a", "e", "z"} {
+		bst.Insert(k, i)
+	}
+
+	var result []string
+	bst.InOrderTraverse(func(key string, value int) {
+		result = append(result, key)
+	})
+	if !isSameSlice(result, []string{"a", "c", "e", "m", "x", "z"}) {
+		t.Fatalf("Traversal order incorrect, got %v", result)
+	}
+	if bst.Nearest("d").Key != "e" && bst.Nearest("d").Key != "c" {
+		t.Fatalf("expected c or e, got %s", bst.Nearest("d").Key)
+	}
+}
+
+func TestComposite(t *testing.T) {
+	type name struct {
+		last, first string
+	}
+
+	bst := NewFunc[name, int](func(a, b name) int {
+		if c := strings.Compare(a.last, b.last); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.first, b.first)
+	})
+	bst.Insert(name{"smith", "john"}, 1)
+	bst.Insert(name{"jones", "mary"}, 2)
+	bst.Insert(name{"smith", "anne"}, 3)
+
+	var result []string
+	bst.InOrderTraverse(func(key name, value int) {
+		result = append(result, key.first+" "+key.last)
+	})
+	if !isSameSlice(result, []string{"mary jones", "anne smith", "john smith"}) {
+		t.Fatalf("Traversal order incorrect, got %v", result)
+	}
+}
+
+func TestDates(t *testing.T) {
+	ranges := []struct {
+		BeginDate time.Time
+		EndDate   time.Time
+		Value     float64
+	}{
+		{
+			time.Date(2009, time.January, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2009, time.June, 1, 0, 0, 0, 0, time.UTC),
+			60.5,
+		},
+		{
+			time.Date(2009, time.June, 1, 0, 0, 0, 1, time.UTC),
+			time.Date(2009, time.December, 1, 0, 0, 0, 0, time.UTC),
+			65.5,
+		},
+		{
+			time.Date(2009, time.December, 1, 0, 0, 0, 1, time.UTC),
+			time.Date(2010, time.June, 1, 0, 0, 0, 0, time.UTC),
+			70.5,
+		},
+		{
+			time.Date(2010, time.June, 1, 0, 0, 0, 1, time.UTC),
+			time.Date(2010, time.December, 1, 0, 0, 0, 0, time.UTC),
+			75.5,
+		},
+		{
+			time.Date(2010, time.December, 1, 0, 0, 0, 1, time.UTC),
+			time.Date(2011, time.June, 1, 0, 0, 0, 0, time.UTC),
+			80.5,
+		},
+	}
+
+	middle := len(ranges) / 2
+	bst := NewFunc[time.Time, float64](time.Time.Compare)
+	bst.Insert(ranges[middle].BeginDate, ranges[middle].Value)
+
+	for i := range ranges {
+		if i == middle {
+			continue
+		}
+		bst.Insert(ranges[i].BeginDate, ranges[i].Value)
+	}
+
+	out := bst.Nearest(time.Date(2009, time.June, 2, 0, 0, 0, 1, time.UTC))
+	if out.Value != 65.5 {
+		t.Fatalf("expected %f, got %f", 65.5, out.Value)
+	}
+}
@@ -0,0 +1,339 @@
+package binarysearchtree
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Interval is the half-open range of integers [Start, End).
+type Interval struct {
+	Start int
+	End   int
+}
+
+// Contains returns true if the point lies within the interval.
+func (i Interval) Contains(point int) bool {
+	return i.Start <= point && point < i.End
+}
+
+// Overlaps returns true if the two intervals share at least one point.
+// Intervals which only touch, such as [1, 2) and [2, 3), don't overlap,
+// and neither does an empty interval.
+func (i Interval) Overlaps(o Interval) bool {
+	return i.Start < o.End && o.Start < i.End && i.Start < i.End && o.Start < o.End
+}
+
+// IntervalVisitor is a function that is called for each matching interval.
+type IntervalVisitor func(iv Interval, value interface{})
+
+// IntervalTree stores intervals and their values in an AVL tree ordered
+// by start. Every node also records the largest end in its subtree,
+// which lets a query skip any subtree that ends before it begins.
+//
+// The same interval can be inserted many times with different values.
+type IntervalTree struct {
+	root *intervalNode
+	size int
+	seq  uint64
+}
+
+type intervalNode struct {
+	Interval
+	value interface{}
+	// seq orders duplicate intervals by insertion.
+	seq uint64
+
+	left, right *intervalNode
+	height      int
+	maxEnd      int
+}
+
+// Insert adds the interval and value to the tree, alongside
+// any entries already stored for the same interval.
+func (t *IntervalTree) Insert(iv Interval, value interface{}) error {
+	if iv.End <= iv.Start {
+		return errors.New("Cannot insert an empty interval")
+	}
+
+	t.seq++
+	t.root = intervalInsert(t.root, &intervalNode{
+		Interval: iv,
+		value:    value,
+		seq:      t.seq,
+		height:   1,
+		maxEnd:   iv.End,
+	})
+	t.size++
+	return nil
+}
+
+func intervalInsert(n, e *intervalNode) *intervalNode {
+	if n == nil {
+		return e
+	}
+
+	if compareIntervals(e, n) < 0 {
+		n.left = intervalInsert(n.left, e)
+	} else {
+		n.right = intervalInsert(n.right, e)
+	}
+	return rebalance(n)
+}
+
+// Delete removes the oldest entry for the interval holding the given
+// value, which must be comparable, and returns whether one was found.
+func (t *IntervalTree) Delete(iv Interval, value interface{}) bool {
+	n := t.root.find(iv, value)
+	if n == nil {
+		return false
+	}
+
+	t.root = intervalRemove(t.root, n)
+	t.size--
+	return true
+}
+
+// find returns the oldest node for the interval holding the value.
+func (n *intervalNode) find(iv Interval, value interface{}) *intervalNode {
+	if n == nil {
+		return nil
+	}
+
+	c := compareStartEnd(iv, n.Interval)
+	if c <= 0 {
+		if found := n.left.find(iv, value); found != nil {
+			return found
+		}
+	}
+	if c == 0 && n.value == value {
+		return n
+	}
+	if c >= 0 {
+		return n.right.find(iv, value)
+	}
+	return nil
+}
+
+func intervalRemove(n, e *intervalNode) *intervalNode {
+	switch c := compareIntervals(e, n); {
+	case c < 0:
+		n.left = intervalRemove(n.left, e)
+	case c > 0:
+		n.right = intervalRemove(n.right, e)
+	default:
+		if n.left == nil {
+			return n.right
+		}
+		if n.right == nil {
+			return n.left
+		}
+
+		smallestRight := n.right
+		for smallestRight.left != nil {
+			smallestRight = smallestRight.left
+		}
+		n.Interval, n.value, n.seq = smallestRight.Interval, smallestRight.value, smallestRight.seq
+		n.right = intervalRemove(n.right, n)
+	}
+	return rebalance(n)
+}
+
+// Len returns the number of entries in the tree.
+func (t *IntervalTree) Len() int {
+	return t.size
+}
+
+// Stabbing calls IntervalVisitor, in order of start, for each
+// entry whose interval contains the point.
+func (t *IntervalTree) Stabbing(point int, v IntervalVisitor) {
+	t.root.stabbing(point, v)
+}
+
+func (n *intervalNode) stabbing(point int, v IntervalVisitor) {
+	if n == nil || n.maxEnd <= point {
+		return
+	}
+
+	n.left.stabbing(point, v)
+	if n.Start > point {
+		// Neither this node nor anything to its right can contain the point.
+		return
+	}
+	if point < n.End {
+		v(n.Interval, n.value)
+	}
+	n.right.stabbing(point, v)
+}
+
+// Overlapping calls IntervalVisitor, in order of start, for each
+// entry whose interval overlaps the given interval.
+func (t *IntervalTree) Overlapping(iv Interval, v IntervalVisitor) {
+	if iv.Start < iv.End {
+		t.root.overlapping(iv, v)
+	}
+}
+
+func (n *intervalNode) overlapping(iv Interval, v IntervalVisitor) {
+	if n == nil || n.maxEnd <= iv.Start {
+		return
+	}
+
+	n.left.overlapping(iv, v)
+	if n.Start >= iv.End {
+		return
+	}
+	if iv.Start < n.End {
+		v(n.Interval, n.value)
+	}
+	n.right.overlapping(iv, v)
+}
+
+// OverlappingPairs calls fn once for every pair of entries in the tree
+// whose intervals overlap, with a starting no later than b.
+func (t *IntervalTree) OverlappingPairs(fn func(a Interval, av interface{}, b Interval, bv interface{})) {
+	// Sweep through the entries in order of start, keeping
+	// those which haven't ended yet.
+	var active []*intervalNode
+	t.root.inOrder(func(n *intervalNode) {
+		live := active[:0]
+		for _, a := range active {
+			if a.End > n.Start {
+				live = append(live, a)
+				fn(a.Interval, a.value, n.Interval, n.value)
+			}
+		}
+		active = append(live, n)
+	})
+}
+
+// InOrderTraverse calls IntervalVisitor for each entry in order of start.
+func (t *IntervalTree) InOrderTraverse(v IntervalVisitor) {
+	t.root.inOrder(func(n *intervalNode) {
+		v(n.Interval, n.value)
+	})
+}
+
+func (n *intervalNode) inOrder(fn func(*intervalNode)) {
+	if n == nil {
+		return
+	}
+	n.left.inOrder(fn)
+	fn(n)
+	n.right.inOrder(fn)
+}
+
+// Validate returns an error if the tree is not ordered, a stored height
+// or max end is wrong, or the subtrees of any node differ in height by
+// more than one.
+func (t *IntervalTree) Validate() error {
+	var prev *intervalNode
+	var err error
+	t.root.inOrder(func(n *intervalNode) {
+		if err == nil && prev != nil && compareIntervals(prev, n) >= 0 {
+			err = fmt.Errorf("interval %v is out of order", n.Interval)
+		}
+		prev = n
+	})
+	if err != nil {
+		return err
+	}
+
+	count, err := validateInterval(t.root)
+	if err != nil {
+		return err
+	}
+	if count != t.size {
+		return fmt.Errorf("expected %d entries, counted %d", t.size, count)
+	}
+	return nil
+}
+
+func validateInterval(n *intervalNode) (int, error) {
+	if n == nil {
+		return 0, nil
+	}
+
+	lc, err := validateInterval(n.left)
+	if err != nil {
+		return 0, err
+	}
+	rc, err := validateInterval(n.right)
+	if err != nil {
+		return 0, err
+	}
+
+	if h := 1 + max(n.left.nodeHeight(), n.right.nodeHeight()); n.height != h {
+		return 0, fmt.Errorf("interval %v has height %d, expected %d", n.Interval, n.height, h)
+	}
+	if bf := n.balanceFactor(); bf < -1 || bf > 1 {
+		return 0, fmt.Errorf("interval %v has balance factor %d", n.Interval, bf)
+	}
+	if m := max(n.End, n.left.subtreeEnd(), n.right.subtreeEnd()); n.maxEnd != m {
+		return 0, fmt.Errorf("interval %v has max end %d, expected %d", n.Interval, n.maxEnd, m)
+	}
+	return lc + rc + 1, nil
+}
+
+// compareStartEnd orders intervals by start and then by end.
+func compareStartEnd(a, b Interval) int {
+	switch {
+	case a.Start < b.Start:
+		return -1
+	case a.Start > b.Start:
+		return 1
+	case a.End < b.End:
+		return -1
+	case a.End > b.End:
+		return 1
+	}
+	return 0
+}
+
+// compareIntervals orders nodes by interval and then by insertion,
+// so that every node in the tree has a distinct position.
+func compareIntervals(a, b *intervalNode) int {
+	if c := compareStartEnd(a.Interval, b.Interval); c != 0 {
+		return c
+	}
+	switch {
+	case a.seq < b.seq:
+		return -1
+	case a.seq > b.seq:
+		return 1
+	}
+	return 0
+}
+
+func (n *intervalNode) nodeHeight() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// subtreeEnd returns the largest end below n, which is
+// smaller than any real end when n is nil.
+func (n *intervalNode) subtreeEnd() int {
+	if n == nil {
+		return math.MinInt
+	}
+	return n.maxEnd
+}
+
+// update recomputes the height and the largest end below n. The
+// rotations call it on each node they move, which keeps maxEnd right.
+func (n *intervalNode) update() {
+	n.height = 1 + max(n.left.nodeHeight(), n.right.nodeHeight())
+	n.maxEnd = max(n.End, n.left.subtreeEnd(), n.right.subtreeEnd())
+}
+
+func (n *intervalNode) balanceFactor() int {
+	return n.left.nodeHeight() - n.right.nodeHeight()
+}
+
+func (n *intervalNode) leftChild() *intervalNode      { return n.left }
+func (n *intervalNode) rightChild() *intervalNode     { return n.right }
+func (n *intervalNode) setLeftChild(c *intervalNode)  { n.left = c }
+func (n *intervalNode) setRightChild(c *intervalNode) { n.right = c }
+func (n *intervalNode) mutable() *intervalNode        { return n }
@@ -0,0 +1,201 @@
+package binarysearchtree
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func schedule() *IntervalTree {
+	var tr IntervalTree
+	for _, iv := range []Interval{
+		{9, 12}, {10, 11}, {13, 17}, {15, 16}, {9, 10}, {17, 18}, {1, 5},
+	} {
+		tr.Insert(iv, fmt.Sprintf("%d-%d", iv.Start, iv.End))
+	}
+	return &tr
+}
+
+func collect(query func(IntervalVisitor)) []string {
+	var result []string
+	query(func(iv Interval, value interface{}) {
+		result = append(result, value.(string))
+	})
+	return result
+}
+
+func TestStabbing(t *testing.T) {
+	tests := map[string]struct {
+		point    int
+		expected []string
+	}{
+		"None":      {0, nil},
+		"Start":     {1, []string{"1-5"}},
+		"End":       {5, nil},
+		"Nested":    {10, []string{"9-12", "10-11"}},
+		"Touching":  {17, []string{"17-18"}},
+		"Inside":    {15, []string{"13-17", "15-16"}},
+		"AfterLast": {18, nil},
+	}
+
+	tr := schedule()
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := collect(func(v IntervalVisitor) { tr.Stabbing(test.point, v) })
+			if !isSameSlice(result, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestOverlapping(t *testing.T) {
+	tests := map[string]struct {
+		iv       Interval
+		expected []string
+	}{
+		"None":     {Interval{6, 9}, nil},
+		"Touching": {Interval{5, 9}, nil},
+		"One":      {Interval{4, 6}, []string{"1-5"}},
+		"Many":     {Interval{11, 14}, []string{"9-12", "13-17"}},
+		"Covering": {Interval{0, 20}, []string{"1-5", "9-10", "9-12", "10-11", "13-17", "15-16", "17-18"}},
+		"Empty":    {Interval{10, 10}, nil},
+	}
+
+	tr := schedule()
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := collect(func(v IntervalVisitor) { tr.Overlapping(test.iv, v) })
+			if !isSameSlice(result, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestIntervalDuplicates(t *testing.T) {
+	var tr IntervalTree
+	iv := Interval{1, 3}
+	for _, v := range []string{"a", "b", "a", "c"} {
+		tr.Insert(iv, v)
+	}
+	if tr.Len() != 4 {
+		t.Fatalf("expected 4 entries, got %d", tr.Len())
+	}
+
+	if !tr.Delete(iv, "a") || !tr.Delete(iv, "c") || tr.Delete(iv, "d") || tr.Delete(Interval{1, 4}, "b") {
+		t.Fatal("delete not working")
+	}
+	if err := tr.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	result := collect(func(v IntervalVisitor) { tr.Stabbing(2, v) })
+	if expected := []string{"b", "a"}; !isSameSlice(result, expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+}
+
+func TestIntervalInsertEmpty(t *testing.T) {
+	var tr IntervalTree
+	if tr.Insert(Interval{2, 2}, nil) == nil || tr.Insert(Interval{3, 2}, nil) == nil {
+		t.Fatal("expected an error")
+	}
+	if tr.Len() != 0 {
+		t.Fatalf("expected an empty tree, got %d entries", tr.Len())
+	}
+}
+
+func TestOverlappingPairs(t *testing.T) {
+	tr := schedule()
+
+	var result []string
+	tr.OverlappingPairs(func(a Interval, av interface{}, b Interval, bv interface{}) {
+		result = append(result, fmt.Sprintf("%s/%s", av, bv))
+	})
+	sort.Strings(result)
+
+	if expected := []string{"13-17/15-16", "9-10/9-12", "9-12/10-11"}; !isSameSlice(result, expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+}
+
+func TestIntervalRandom(t *testing.T) {
+	type entry struct {
+		iv    Interval
+		value int
+	}
+
+	r := rand.New(rand.NewSource(1))
+	var tr IntervalTree
+	var model []entry
+
+	for i := 0; i < 2000; i++ {
+		if len(model) > 0 && r.Intn(3) == 0 {
+			j := r.Intn(len(model))
+			if !tr.Delete(model[j].iv, model[j].value) {
+				t.Fatalf("failed to delete %v", model[j])
+			}
+			model = append(model[:j], model[j+1:]...)
+		} else {
+			start := r.Intn(200)
+			e := entry{Interval{start, start + 1 + r.Intn(20)}, r.Intn(3)}
+			tr.Insert(e.iv, e.value)
+			model = append(model, e)
+		}
+
+		if err := tr.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		start := r.Intn(220)
+		q := Interval{start, start + 1 + r.Intn(10)}
+
+		want, got := 0, 0
+		for _, e := range model {
+			if e.iv.Overlaps(q) {
+				want++
+			}
+		}
+		tr.Overlapping(q, func(iv Interval, value interface{}) {
+			if !iv.Overlaps(q) {
+				t.Fatalf("%v doesn't overlap %v", iv, q)
+			}
+			got++
+		})
+		if want != got {
+			t.Fatalf("%v: expected %d overlaps, got %d", q, want, got)
+		}
+
+		want, got = 0, 0
+		for _, e := range model {
+			if e.iv.Contains(q.Start) {
+				want++
+			}
+		}
+		tr.Stabbing(q.Start, func(iv Interval, value interface{}) {
+			got++
+		})
+		if want != got {
+			t.Fatalf("%d: expected %d intervals, got %d", q.Start, want, got)
+		}
+	}
+
+	want, got := 0, 0
+	for i := range model {
+		for j := i + 1; j < len(model); j++ {
+			if model[i].iv.Overlaps(model[j].iv) {
+				want++
+			}
+		}
+	}
+	tr.OverlappingPairs(func(a Interval, av interface{}, b Interval, bv interface{}) {
+		got++
+	})
+	if want != got {
+		t.Fatalf("expected %d overlapping pairs, got %d", want, got)
+	}
+}
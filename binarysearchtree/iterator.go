@@ -0,0 +1,140 @@
+package binarysearchtree
+
+// Iterator steps through the keys of a tree in order, in either
+// direction. It holds the path from the root to the current node on
+// an explicit stack, so each step is O(1) amortised and the depth of
+// the tree is limited only by memory.
+//
+// A new Iterator is unpositioned: Next moves it to the first key and
+// Prev to the last. Once Next or Prev runs off either end it is
+// unpositioned again. Modifying the tree invalidates the iterator.
+type Iterator struct {
+	root  *Node
+	stack []*Node
+}
+
+// Iterator returns an unpositioned iterator over the tree rooted at n.
+func (n *Node) Iterator() *Iterator {
+	return &Iterator{root: n}
+}
+
+// Valid returns true if the iterator is positioned at a key.
+func (it *Iterator) Valid() bool {
+	return len(it.stack) > 0
+}
+
+// Key returns the key at the current position.
+// It panics if the iterator isn't Valid.
+func (it *Iterator) Key() int {
+	return it.stack[len(it.stack)-1].Key
+}
+
+// Value returns the value at the current position.
+// It panics if the iterator isn't Valid.
+func (it *Iterator) Value() interface{} {
+	return it.stack[len(it.stack)-1].Value
+}
+
+// First moves to the smallest key and returns false if the tree is empty.
+func (it *Iterator) First() bool {
+	it.stack = it.stack[:0]
+	it.pushLeft(it.root)
+	return it.Valid()
+}
+
+// Last moves to the largest key and returns false if the tree is empty.
+func (it *Iterator) Last() bool {
+	it.stack = it.stack[:0]
+	it.pushRight(it.root)
+	return it.Valid()
+}
+
+// Seek moves to the smallest key greater than or equal to the
+// specified key and returns false if there isn't one.
+func (it *Iterator) Seek(key int) bool {
+	it.stack = it.stack[:0]
+	found := 0
+	for n := it.root; n != nil; {
+		it.stack = append(it.stack, n)
+		switch {
+		case key < n.Key:
+			found = len(it.stack)
+			n = n.Left
+		case key > n.Key:
+			n = n.Right
+		default:
+			found = len(it.stack)
+			n = nil
+		}
+	}
+
+	// Trim the path back to the last node that could be the ceiling.
+	it.stack = it.stack[:found]
+	return it.Valid()
+}
+
+// Next moves to the next key in ascending order
+// and returns false if there isn't one.
+func (it *Iterator) Next() bool {
+	if !it.Valid() {
+		return it.First()
+	}
+
+	if n := it.stack[len(it.stack)-1]; n.Right != nil {
+		it.pushLeft(n.Right)
+		return true
+	}
+
+	// Climb until we leave a left subtree; its parent is next.
+	for {
+		child := it.pop()
+		if !it.Valid() {
+			return false
+		}
+		if it.stack[len(it.stack)-1].Left == child {
+			return true
+		}
+	}
+}
+
+// Prev moves to the previous key in ascending order
+// and returns false if there isn't one.
+func (it *Iterator) Prev() bool {
+	if !it.Valid() {
+		return it.Last()
+	}
+
+	if n := it.stack[len(it.stack)-1]; n.Left != nil {
+		it.pushRight(n.Left)
+		return true
+	}
+
+	for {
+		child := it.pop()
+		if !it.Valid() {
+			return false
+		}
+		if it.stack[len(it.stack)-1].Right == child {
+			return true
+		}
+	}
+}
+
+func (it *Iterator) pushLeft(n *Node) {
+	for ; n != nil; n = n.Left {
+		it.stack = append(it.stack, n)
+	}
+}
+
+func (it *Iterator) pushRight(n *Node) {
+	for ; n != nil; n = n.Right {
+		it.stack = append(it.stack, n)
+	}
+}
+
+func (it *Iterator) pop() *Node {
+	n := it.stack[len(it.stack)-1]
+	it.stack[len(it.stack)-1] = nil
+	it.stack = it.stack[:len(it.stack)-1]
+	return n
+}
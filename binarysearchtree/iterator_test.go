@@ -0,0 +1,116 @@
+package binarysearchtree
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestIteratorForward(t *testing.T) {
+	it := gapTree().Iterator()
+	if it.Valid() {
+		t.Fatal("expected a new iterator to be unpositioned")
+	}
+
+	var result []string
+	for it.Next() {
+		result = append(result, it.Value().(string))
+	}
+	expected := []string{"10", "20", "30", "40", "50", "60", "70", "80", "90", "100", "110"}
+	if !isSameSlice(result, expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+	if it.Valid() {
+		t.Fatal("expected an exhausted iterator to be unpositioned")
+	}
+}
+
+func TestIteratorBackward(t *testing.T) {
+	it := gapTree().Iterator()
+
+	var result []string
+	for it.Prev() {
+		result = append(result, fmt.Sprint(it.Key()))
+	}
+	expected := []string{"110", "100", "90", "80", "70", "60", "50", "40", "30", "20", "10"}
+	if !isSameSlice(result, expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+}
+
+func TestIteratorSeek(t *testing.T) {
+	tests := map[string]struct {
+		key   int
+		found bool
+		at    int
+		next  int
+		prev  int
+	}{
+		"BelowMin": {5, true, 10, 20, -1},
+		"Exact":    {50, true, 50, 60, 40},
+		"Between":  {55, true, 60, 70, 50},
+		"Root":     {80, true, 80, 90, 70},
+		"Max":      {110, true, 110, -1, 100},
+		"AboveMax": {120, false, 0, 0, 0},
+	}
+
+	root := gapTree()
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			it := root.Iterator()
+			if found := it.Seek(test.key); found != test.found {
+				t.Fatalf("expected %v, got %v", test.found, found)
+			}
+			if !test.found {
+				return
+			}
+			if it.Key() != test.at {
+				t.Fatalf("expected %d, got %d", test.at, it.Key())
+			}
+
+			next := it.Next()
+			if (test.next == -1) == next || (next && it.Key() != test.next) {
+				t.Fatalf("next: expected %d, got %v", test.next, next)
+			}
+
+			it.Seek(test.key)
+			prev := it.Prev()
+			if (test.prev == -1) == prev || (prev && it.Key() != test.prev) {
+				t.Fatalf("prev: expected %d, got %v", test.prev, prev)
+			}
+		})
+	}
+}
+
+func TestIteratorChangeDirection(t *testing.T) {
+	var tr AVLTree
+	for i := 0; i < 100; i++ {
+		tr.Insert(i, i)
+	}
+
+	it := tr.Iterator()
+	if !it.First() || it.Key() != 0 {
+		t.Fatal("expected first key 0")
+	}
+	if !it.Last() || it.Key() != 99 {
+		t.Fatal("expected last key 99")
+	}
+
+	it.Seek(50)
+	for i := 0; i < 10; i++ {
+		it.Next()
+	}
+	for i := 0; i < 15; i++ {
+		it.Prev()
+	}
+	if it.Key() != 45 {
+		t.Fatalf("expected 45, got %d", it.Key())
+	}
+}
+
+func TestIteratorEmpty(t *testing.T) {
+	var tr Tree
+	it := tr.Iterator()
+	if it.Next() || it.Prev() || it.First() || it.Last() || it.Seek(0) {
+		t.Fatal("expected nothing from an empty tree")
+	}
+}
@@ -0,0 +1,182 @@
+package binarysearchtree
+
+import "fmt"
+
+// MultiTree is an ordered multimap: inserting a key which is already
+// in the tree adds another value for it rather than replacing the one
+// there. Each key's values are kept in the order they were inserted,
+// so that, for example, the orders at each price in an order book are
+// filled first come, first served.
+//
+// The keys are held in an AVLTree, each with a list of its values,
+// so finding a key takes O(log k) for k distinct keys however many
+// duplicates each has.
+type MultiTree struct {
+	keys AVLTree
+	size int
+}
+
+// Insert adds the value to the end of the key's values.
+func (t *MultiTree) Insert(key int, value interface{}) {
+	if n := t.keys.root.Exact(key); n != nil {
+		n.Value = append(n.Value.([]interface{}), value)
+	} else {
+		t.keys.Insert(key, []interface{}{value})
+	}
+	t.size++
+}
+
+// Count returns the number of values held for the key.
+func (t *MultiTree) Count(key int) int {
+	return len(t.values(key))
+}
+
+// Values returns a copy of the key's values, oldest first,
+// or nil if the key isn't in the tree.
+func (t *MultiTree) Values(key int) []interface{} {
+	values := t.values(key)
+	if values == nil {
+		return nil
+	}
+	return append([]interface{}(nil), values...)
+}
+
+// Search returns true if the given key is found within the tree.
+func (t *MultiTree) Search(key int) bool {
+	return t.keys.Search(key)
+}
+
+// RemoveOne removes the oldest of the key's values and returns it.
+func (t *MultiTree) RemoveOne(key int) (interface{}, bool) {
+	n := t.keys.root.Exact(key)
+	if n == nil {
+		return nil, false
+	}
+
+	values := n.Value.([]interface{})
+	value := values[0]
+	if len(values) == 1 {
+		t.keys.Delete(key)
+	} else {
+		// Clear the slot so the backing array doesn't keep the value alive.
+		values[0] = nil
+		n.Value = values[1:]
+	}
+	t.size--
+	return value, true
+}
+
+// RemoveAll removes the key and returns all of its values, oldest first.
+func (t *MultiTree) RemoveAll(key int) ([]interface{}, bool) {
+	values, ok := t.keys.Delete(key)
+	if !ok {
+		return nil, false
+	}
+	t.size -= len(values.([]interface{}))
+	return values.([]interface{}), true
+}
+
+// Len returns the number of values in the tree, counting every
+// value of a duplicated key.
+func (t *MultiTree) Len() int {
+	return t.size
+}
+
+// KeyCount returns the number of distinct keys in the tree.
+func (t *MultiTree) KeyCount() int {
+	return t.keys.Len()
+}
+
+// Clear removes every key from the tree.
+func (t *MultiTree) Clear() {
+	t.keys.Clear()
+	t.size = 0
+}
+
+// Min returns the min key in the tree and its oldest value.
+func (t *MultiTree) Min() (int, interface{}, bool) {
+	return first(t.keys.Min())
+}
+
+// Max returns the max key in the tree and its oldest value.
+func (t *MultiTree) Max() (int, interface{}, bool) {
+	return first(t.keys.Max())
+}
+
+// InOrderTraverse calls Visitor for each value in ascending order of
+// key, so a duplicated key is visited once for each of its values,
+// oldest first.
+func (t *MultiTree) InOrderTraverse(v Visitor) {
+	t.InOrderWalk(visitAll(v))
+}
+
+// InOrderWalk calls fn for each value in ascending order of key,
+// oldest first for a duplicated key, until it returns false.
+func (t *MultiTree) InOrderWalk(fn WalkFn) {
+	t.keys.InOrderWalk(eachValue(fn))
+}
+
+// Range calls Visitor for each value of each key between lo and hi
+// inclusive, in the same order as InOrderTraverse.
+func (t *MultiTree) Range(lo, hi int, v Visitor) {
+	walk := eachValue(visitAll(v))
+	t.keys.Range(lo, hi, func(key int, values interface{}) {
+		walk(key, values)
+	})
+}
+
+// Validate returns an error if the tree of keys isn't a valid AVLTree,
+// a key has no values, or the values don't add up to Len.
+func (t *MultiTree) Validate() error {
+	if err := t.keys.Validate(); err != nil {
+		return err
+	}
+
+	count := 0
+	var err error
+	t.keys.InOrderWalk(func(key int, values interface{}) bool {
+		n := len(values.([]interface{}))
+		if n == 0 {
+			err = fmt.Errorf("key %d has no values", key)
+			return false
+		}
+		count += n
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if count != t.size {
+		return fmt.Errorf("expected %d values, counted %d", t.size, count)
+	}
+	return nil
+}
+
+func (t *MultiTree) values(key int) []interface{} {
+	values, ok := t.keys.Get(key)
+	if !ok {
+		return nil
+	}
+	return values.([]interface{})
+}
+
+// eachValue adapts fn to walk the keys of a MultiTree,
+// calling it for each value of each key.
+func eachValue(fn WalkFn) WalkFn {
+	return func(key int, values interface{}) bool {
+		for _, v := range values.([]interface{}) {
+			if !fn(key, v) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// first returns the key and the oldest of its values.
+func first(key int, values interface{}, ok bool) (int, interface{}, bool) {
+	if !ok {
+		return 0, nil, false
+	}
+	return key, values.([]interface{})[0], true
+}
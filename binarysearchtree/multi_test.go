@@ -0,0 +1,187 @@
+package binarysearchtree
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// visited returns the entries visited by a traversal as strings.
+func visited(traverse func(Visitor)) []string {
+	var result []string
+	traverse(func(key int, value interface{}) {
+		result = append(result, fmt.Sprint(key, ":", value))
+	})
+	return result
+}
+
+func TestMultiTree(t *testing.T) {
+	var tr MultiTree
+	if _, ok := tr.RemoveOne(1); ok {
+		t.Fatal("expected nothing to remove")
+	}
+	if _, _, ok := tr.Min(); ok {
+		t.Fatal("expected no min")
+	}
+
+	// Orders at each price, in the order they arrived.
+	for _, order := range []struct {
+		price int
+		id    string
+	}{
+		{101, "a"}, {99, "b"}, {101, "c"}, {100, "d"}, {99, "e"}, {101, "f"},
+	} {
+		tr.Insert(order.price, order.id)
+	}
+
+	if tr.Len() != 6 || tr.KeyCount() != 3 {
+		t.Fatalf("expected 6 values and 3 keys, got %d and %d", tr.Len(), tr.KeyCount())
+	}
+	for key, count := range map[int]int{99: 2, 100: 1, 101: 3, 102: 0} {
+		if c := tr.Count(key); c != count {
+			t.Fatalf("%d: expected %d values, got %d", key, count, c)
+		}
+	}
+	if v := fmt.Sprint(tr.Values(101)); v != "[a c f]" {
+		t.Fatalf("expected [a c f], got %s", v)
+	}
+	if tr.Values(102) != nil {
+		t.Fatal("expected no values")
+	}
+
+	expected := []string{"99:b", "99:e", "100:d", "101:a", "101:c", "101:f"}
+	if result := visited(tr.InOrderTraverse); !isSameSlice(result, expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+	if result := visited(func(v Visitor) { tr.Range(100, 101, v) }); !isSameSlice(result, expected[2:]) {
+		t.Fatalf("expected %v, got %v", expected[2:], result)
+	}
+
+	var walked []string
+	tr.InOrderWalk(func(key int, value interface{}) bool {
+		walked = append(walked, fmt.Sprint(key, ":", value))
+		return len(walked) < 4
+	})
+	if !isSameSlice(walked, expected[:4]) {
+		t.Fatalf("expected %v, got %v", expected[:4], walked)
+	}
+
+	if k, v, _ := tr.Min(); k != 99 || v != "b" {
+		t.Fatalf("expected 99:b, got %d:%v", k, v)
+	}
+	if k, v, _ := tr.Max(); k != 101 || v != "a" {
+		t.Fatalf("expected 101:a, got %d:%v", k, v)
+	}
+
+	// Filling the best bid takes the oldest order first.
+	if v, ok := tr.RemoveOne(101); !ok || v != "a" {
+		t.Fatalf("expected a, got %v", v)
+	}
+	if v, ok := tr.RemoveOne(100); !ok || v != "d" {
+		t.Fatalf("expected d, got %v", v)
+	}
+	if tr.Search(100) {
+		t.Fatal("expected the last value to take the key with it")
+	}
+	if v, ok := tr.RemoveAll(99); !ok || fmt.Sprint(v) != "[b e]" {
+		t.Fatalf("expected [b e], got %v", v)
+	}
+	if _, ok := tr.RemoveAll(99); ok {
+		t.Fatal("expected nothing to remove")
+	}
+
+	expected = []string{"101:c", "101:f"}
+	if result := visited(tr.InOrderTraverse); !isSameSlice(result, expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+	if tr.Len() != 2 || tr.KeyCount() != 1 {
+		t.Fatalf("expected 2 values and 1 key, got %d and %d", tr.Len(), tr.KeyCount())
+	}
+	if err := tr.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tr.Clear()
+	if tr.Len() != 0 || tr.KeyCount() != 0 {
+		t.Fatal("expected an empty tree")
+	}
+}
+
+func TestMultiTreeRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var tr MultiTree
+	model := make(map[int][]int)
+	size := 0
+
+	for i := 0; i < 5000; i++ {
+		key := r.Intn(50)
+		switch r.Intn(5) {
+		case 0, 1, 2:
+			tr.Insert(key, i)
+			model[key] = append(model[key], i)
+			size++
+		case 3:
+			v, ok := tr.RemoveOne(key)
+			if len(model[key]) == 0 {
+				if ok {
+					t.Fatalf("remove one %d: expected nothing, got %v", key, v)
+				}
+				break
+			}
+			if !ok || v != model[key][0] {
+				t.Fatalf("remove one %d: expected %d, got %v", key, model[key][0], v)
+			}
+			model[key] = model[key][1:]
+			size--
+		case 4:
+			v, ok := tr.RemoveAll(key)
+			if ok != (len(model[key]) > 0) || (ok && fmt.Sprint(v) != fmt.Sprint(model[key])) {
+				t.Fatalf("remove all %d: expected %v, got %v", key, model[key], v)
+			}
+			size -= len(model[key])
+			model[key] = nil
+		}
+
+		if c := tr.Count(key); c != len(model[key]) {
+			t.Fatalf("count %d: expected %d, got %d", key, len(model[key]), c)
+		}
+		if tr.Len() != size {
+			t.Fatalf("expected %d values, got %d", size, tr.Len())
+		}
+		if err := tr.Validate(); err != nil {
+			t.Fatalf("after %d operations: %v", i+1, err)
+		}
+	}
+
+	last, count := -1, 0
+	tr.InOrderTraverse(func(key int, value interface{}) {
+		if key < last {
+			t.Fatalf("key %d visited after %d", key, last)
+		}
+		if key != last {
+			count = 0
+		}
+		if value != model[key][count] {
+			t.Fatalf("%d: expected %d, got %v", key, model[key][count], value)
+		}
+		last = key
+		count++
+	})
+}
+
+func TestMultiTreeValidate(t *testing.T) {
+	var tr MultiTree
+	tr.Insert(1, "a")
+	tr.Insert(1, "b")
+
+	tr.size = 3
+	if err := tr.Validate(); err == nil {
+		t.Fatal("expected a count error")
+	}
+
+	tr.size = 0
+	tr.keys.root.Value = []interface{}{}
+	if err := tr.Validate(); err == nil {
+		t.Fatal("expected an error for a key without values")
+	}
+}
@@ -0,0 +1,345 @@
+package binarysearchtree
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// PersistentTree is an immutable AVL tree. Insert and Remove return
+// a new version of the tree, copying only the O(log n) nodes on the
+// path to the key and sharing every other subtree with the version
+// they were called on, which stays valid and unchanged.
+//
+// Copying a PersistentTree is therefore a free snapshot, and because
+// no version is ever modified any number of goroutines can read them
+// without locking. The zero value is an empty tree.
+type PersistentTree struct {
+	root *persistentNode
+	size int
+}
+
+// persistentNode is never modified once it is reachable from a tree.
+type persistentNode struct {
+	key         int
+	value       interface{}
+	left, right *persistentNode
+	height      int
+}
+
+// Insert returns a version of the tree with the key set to the value.
+func (t PersistentTree) Insert(key int, value interface{}) PersistentTree {
+	var added bool
+	t.root = persistentInsert(t.root, key, value, &added)
+	if added {
+		t.size++
+	}
+	return t
+}
+
+func persistentInsert(n *persistentNode, key int, value interface{}, added *bool) *persistentNode {
+	if n == nil {
+		*added = true
+		return &persistentNode{key: key, value: value, height: 1}
+	}
+
+	c := n.clone()
+	switch {
+	case key < n.key:
+		c.left = persistentInsert(n.left, key, value, added)
+	case key > n.key:
+		c.right = persistentInsert(n.right, key, value, added)
+	default:
+		c.value = value
+		return c
+	}
+	return rebalance(c)
+}
+
+// Remove returns a version of the tree without the key, and whether
+// the key was found. If it wasn't the tree is returned unchanged.
+func (t PersistentTree) Remove(key int) (PersistentTree, bool) {
+	var removed bool
+	t.root = persistentRemove(t.root, key, &removed)
+	if removed {
+		t.size--
+	}
+	return t, removed
+}
+
+func persistentRemove(n *persistentNode, key int, removed *bool) *persistentNode {
+	if n == nil {
+		return nil
+	}
+
+	switch {
+	case key < n.key:
+		left := persistentRemove(n.left, key, removed)
+		if !*removed {
+			return n
+		}
+		c := n.clone()
+		c.left = left
+		return rebalance(c)
+	case key > n.key:
+		right := persistentRemove(n.right, key, removed)
+		if !*removed {
+			return n
+		}
+		c := n.clone()
+		c.right = right
+		return rebalance(c)
+	}
+
+	*removed = true
+	if n.left == nil {
+		return n.right
+	}
+	if n.right == nil {
+		return n.left
+	}
+
+	smallestRight := n.right
+	for smallestRight.left != nil {
+		smallestRight = smallestRight.left
+	}
+	c := n.clone()
+	c.key, c.value = smallestRight.key, smallestRight.value
+	c.right = persistentRemoveMin(n.right)
+	return rebalance(c)
+}
+
+func persistentRemoveMin(n *persistentNode) *persistentNode {
+	if n.left == nil {
+		return n.right
+	}
+	c := n.clone()
+	c.left = persistentRemoveMin(n.left)
+	return rebalance(c)
+}
+
+// Get returns the value associated with the key.
+func (t PersistentTree) Get(key int) (interface{}, bool) {
+	for n := t.root; n != nil; {
+		switch {
+		case key < n.key:
+			n = n.left
+		case key > n.key:
+			n = n.right
+		default:
+			return n.value, true
+		}
+	}
+	return nil, false
+}
+
+// Len returns the number of keys in the tree.
+func (t PersistentTree) Len() int {
+	return t.size
+}
+
+// Height returns the number of nodes on the longest path from the
+// root to a leaf, zero for an empty tree.
+func (t PersistentTree) Height() int {
+	return t.root.nodeHeight()
+}
+
+// Min returns the min key in the tree and its value.
+func (t PersistentTree) Min() (int, interface{}, bool) {
+	if t.root == nil {
+		return 0, nil, false
+	}
+	n := t.root
+	for n.left != nil {
+		n = n.left
+	}
+	return n.key, n.value, true
+}
+
+// Max returns the max key in the tree and its value.
+func (t PersistentTree) Max() (int, interface{}, bool) {
+	if t.root == nil {
+		return 0, nil, false
+	}
+	n := t.root
+	for n.right != nil {
+		n = n.right
+	}
+	return n.key, n.value, true
+}
+
+// InOrderTraverse calls Visitor for each key in ascending order.
+func (t PersistentTree) InOrderTraverse(v Visitor) {
+	t.root.inOrder(func(n *persistentNode) {
+		v(n.key, n.value)
+	})
+}
+
+func (n *persistentNode) inOrder(fn func(*persistentNode)) {
+	if n == nil {
+		return
+	}
+	n.left.inOrder(fn)
+	fn(n)
+	n.right.inOrder(fn)
+}
+
+// DiffKind describes how a key differs between two versions of a tree.
+type DiffKind int
+
+// The ways a key can differ between an older and a newer version.
+const (
+	Added DiffKind = iota + 1
+	Removed
+	Changed
+)
+
+func (k DiffKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	}
+	return fmt.Sprintf("DiffKind(%d)", int(k))
+}
+
+// DiffFn is called for each key which differs between two versions,
+// with its old and new values. The old value is nil for added keys
+// and the new value nil for removed keys.
+type DiffFn func(key int, kind DiffKind, oldValue, newValue interface{})
+
+// Diff calls fn, in ascending order of key, for every key added,
+// removed or changed in newer relative to t. Values are compared with
+// reflect.DeepEqual. Subtrees shared by the two versions are skipped,
+// so diffing versions a few edits apart takes time proportional to
+// the edits rather than to the size of the tree.
+func (t PersistentTree) Diff(newer PersistentTree, fn DiffFn) {
+	diff(t.root, newer.root, fn)
+}
+
+func diff(older, newer *persistentNode, fn DiffFn) {
+	if older == newer {
+		return
+	}
+	if older == nil {
+		newer.inOrder(func(n *persistentNode) {
+			fn(n.key, Added, nil, n.value)
+		})
+		return
+	}
+	if newer == nil {
+		older.inOrder(func(n *persistentNode) {
+			fn(n.key, Removed, n.value, nil)
+		})
+		return
+	}
+
+	// Split the newer subtree around the older root, so each side can
+	// be compared with the matching side of the older subtree.
+	lt, eq, gt := split(newer, older.key)
+	diff(older.left, lt, fn)
+	switch {
+	case eq == nil:
+		fn(older.key, Removed, older.value, nil)
+	case !reflect.DeepEqual(older.value, eq.value):
+		fn(older.key, Changed, older.value, eq.value)
+	}
+	diff(older.right, gt, fn)
+}
+
+// split returns the parts of the subtree rooted at n with keys less
+// than, equal to and greater than key. The parts share every subtree
+// off the search path with n, and aren't balanced, so they are only
+// fit for diff.
+func split(n *persistentNode, key int) (lt, eq, gt *persistentNode) {
+	if n == nil {
+		return nil, nil, nil
+	}
+
+	switch {
+	case key < n.key:
+		lt, eq, gt = split(n.left, key)
+		c := n.clone()
+		c.left = gt
+		return lt, eq, c
+	case key > n.key:
+		lt, eq, gt = split(n.right, key)
+		c := n.clone()
+		c.right = lt
+		return c, eq, gt
+	}
+	return n.left, n, n.right
+}
+
+// Validate returns an error if the tree is not ordered, a stored height
+// is wrong, or the subtrees of any node differ in height by more than
+// one.
+func (t PersistentTree) Validate() error {
+	_, count, err := validatePersistent(t.root, nil, nil)
+	if err != nil {
+		return err
+	}
+	if count != t.size {
+		return fmt.Errorf("expected %d nodes, counted %d", t.size, count)
+	}
+	return nil
+}
+
+func validatePersistent(n *persistentNode, lo, hi *int) (int, int, error) {
+	if n == nil {
+		return 0, 0, nil
+	}
+	if (lo != nil && n.key <= *lo) || (hi != nil && n.key >= *hi) {
+		return 0, 0, fmt.Errorf("key %d is out of order", n.key)
+	}
+
+	lh, lc, err := validatePersistent(n.left, lo, &n.key)
+	if err != nil {
+		return 0, 0, err
+	}
+	rh, rc, err := validatePersistent(n.right, &n.key, hi)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	h := 1 + max(lh, rh)
+	if n.height != h {
+		return 0, 0, fmt.Errorf("key %d has height %d, expected %d", n.key, n.height, h)
+	}
+	if bf := lh - rh; bf < -1 || bf > 1 {
+		return 0, 0, fmt.Errorf("key %d has balance factor %d", n.key, bf)
+	}
+	return h, lc + rc + 1, nil
+}
+
+func (n *persistentNode) clone() *persistentNode {
+	c := *n
+	return &c
+}
+
+func (n *persistentNode) nodeHeight() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func (n *persistentNode) balanceFactor() int {
+	return n.left.nodeHeight() - n.right.nodeHeight()
+}
+
+func (n *persistentNode) update() {
+	n.height = 1 + max(n.left.nodeHeight(), n.right.nodeHeight())
+}
+
+// The nodes on the path to a key are copied before rebalance is
+// called on them, but the children it rotates with may be shared
+// with other versions, so it copies those through mutable.
+
+func (n *persistentNode) leftChild() *persistentNode      { return n.left }
+func (n *persistentNode) rightChild() *persistentNode     { return n.right }
+func (n *persistentNode) setLeftChild(c *persistentNode)  { n.left = c }
+func (n *persistentNode) setRightChild(c *persistentNode) { n.right = c }
+func (n *persistentNode) mutable() *persistentNode        { return n.clone() }
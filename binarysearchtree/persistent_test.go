@@ -0,0 +1,277 @@
+package binarysearchtree
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// contents returns the entries of the tree as a map.
+func contents(t PersistentTree) map[int]interface{} {
+	m := make(map[int]interface{})
+	t.InOrderTraverse(func(key int, value interface{}) {
+		m[key] = value
+	})
+	return m
+}
+
+func TestPersistentVersions(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	var versions []PersistentTree
+	var models []map[int]interface{}
+	var tr PersistentTree
+	model := make(map[int]interface{})
+
+	for i := 0; i < 500; i++ {
+		key := r.Intn(100)
+		if r.Intn(3) == 0 {
+			var ok bool
+			tr, ok = tr.Remove(key)
+			if _, mok := model[key]; ok != mok {
+				t.Fatalf("remove %d: expected %v, got %v", key, mok, ok)
+			}
+			delete(model, key)
+		} else {
+			tr = tr.Insert(key, i)
+			model[key] = i
+		}
+
+		if err := tr.Validate(); err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := make(map[int]interface{}, len(model))
+		for k, v := range model {
+			snapshot[k] = v
+		}
+		versions = append(versions, tr)
+		models = append(models, snapshot)
+	}
+
+	// Every old version must be exactly as it was when it was made.
+	for i, v := range versions {
+		if got := contents(v); fmt.Sprint(got) != fmt.Sprint(models[i]) {
+			t.Fatalf("version %d: expected %v, got %v", i, models[i], got)
+		}
+		if v.Len() != len(models[i]) {
+			t.Fatalf("version %d: expected %d keys, got %d", i, len(models[i]), v.Len())
+		}
+	}
+}
+
+func TestPersistentSharing(t *testing.T) {
+	var tr PersistentTree
+	for i := 0; i < 1000; i++ {
+		tr = tr.Insert(i, i)
+	}
+
+	nodes := func(t PersistentTree) map[*persistentNode]bool {
+		set := make(map[*persistentNode]bool)
+		t.root.inOrder(func(n *persistentNode) {
+			set[n] = true
+		})
+		return set
+	}
+
+	before := nodes(tr)
+	for _, next := range []PersistentTree{
+		tr.Insert(500, "changed"),
+		tr.Insert(1000, 1000),
+		func() PersistentTree { next, _ := tr.Remove(500); return next }(),
+	} {
+		copied := 0
+		for n := range nodes(next) {
+			if !before[n] {
+				copied++
+			}
+		}
+		// A rebalance can copy a sibling for every node on the path.
+		if max := 2 * tr.Height(); copied > max {
+			t.Fatalf("expected at most %d new nodes, got %d", max, copied)
+		}
+	}
+
+	if next, ok := tr.Remove(5000); ok || next.root != tr.root {
+		t.Fatal("expected removing a missing key to return the same tree")
+	}
+}
+
+func TestPersistentEmpty(t *testing.T) {
+	var tr PersistentTree
+	if _, ok := tr.Get(1); ok {
+		t.Fatal("expected no value")
+	}
+	if _, _, ok := tr.Min(); ok {
+		t.Fatal("expected no min")
+	}
+	if _, ok := tr.Remove(1); ok {
+		t.Fatal("expected nothing to remove")
+	}
+
+	tr = tr.Insert(2, "2").Insert(1, "1").Insert(3, "3")
+	if k, _, _ := tr.Min(); k != 1 {
+		t.Fatalf("expected min 1, got %d", k)
+	}
+	if k, _, _ := tr.Max(); k != 3 {
+		t.Fatalf("expected max 3, got %d", k)
+	}
+	if v, ok := tr.Get(2); !ok || v != "2" {
+		t.Fatalf("expected 2, got %v", v)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	var base PersistentTree
+	for i := 0; i < 10; i++ {
+		base = base.Insert(i, fmt.Sprint(i))
+	}
+
+	tests := map[string]struct {
+		edit     func(PersistentTree) PersistentTree
+		expected []string
+	}{
+		"Same": {
+			func(t PersistentTree) PersistentTree { return t },
+			nil,
+		},
+		"SameValue": {
+			func(t PersistentTree) PersistentTree { return t.Insert(4, "4") },
+			nil,
+		},
+		"Added": {
+			func(t PersistentTree) PersistentTree { return t.Insert(20, "20").Insert(-1, "-1") },
+			[]string{"-1 added <nil> -1", "20 added <nil> 20"},
+		},
+		"Removed": {
+			func(t PersistentTree) PersistentTree { t, _ = t.Remove(3); t, _ = t.Remove(7); return t },
+			[]string{"3 removed 3 <nil>", "7 removed 7 <nil>"},
+		},
+		"Changed": {
+			func(t PersistentTree) PersistentTree { return t.Insert(5, "five") },
+			[]string{"5 changed 5 five"},
+		},
+		"Mixed": {
+			func(t PersistentTree) PersistentTree {
+				t, _ = t.Remove(0)
+				return t.Insert(9, "nine").Insert(15, "15")
+			},
+			[]string{"0 removed 0 <nil>", "9 changed 9 nine", "15 added <nil> 15"},
+		},
+		"Cleared": {
+			func(t PersistentTree) PersistentTree { return PersistentTree{} },
+			[]string{
+				"0 removed 0 <nil>", "1 removed 1 <nil>", "2 removed 2 <nil>", "3 removed 3 <nil>",
+				"4 removed 4 <nil>", "5 removed 5 <nil>", "6 removed 6 <nil>", "7 removed 7 <nil>",
+				"8 removed 8 <nil>", "9 removed 9 <nil>",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var result []string
+			base.Diff(test.edit(base), func(key int, kind DiffKind, oldValue, newValue interface{}) {
+				result = append(result, fmt.Sprint(key, " ", kind, " ", oldValue, " ", newValue))
+			})
+			if !isSameSlice(result, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestDiffRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	var older PersistentTree
+	for i := 0; i < 1000; i++ {
+		older = older.Insert(r.Intn(2000), r.Intn(5))
+	}
+	newer := older
+	for i := 0; i < 200; i++ {
+		if r.Intn(2) == 0 {
+			newer, _ = newer.Remove(r.Intn(2000))
+		} else {
+			newer = newer.Insert(r.Intn(2000), r.Intn(5))
+		}
+	}
+
+	om, nm := contents(older), contents(newer)
+	var expected []string
+	for k, v := range om {
+		if nv, ok := nm[k]; !ok {
+			expected = append(expected, fmt.Sprint(k, Removed))
+		} else if nv != v {
+			expected = append(expected, fmt.Sprint(k, Changed))
+		}
+	}
+	for k := range nm {
+		if _, ok := om[k]; !ok {
+			expected = append(expected, fmt.Sprint(k, Added))
+		}
+	}
+	sort.Strings(expected)
+
+	var result []string
+	last := -1
+	older.Diff(newer, func(key int, kind DiffKind, oldValue, newValue interface{}) {
+		if key <= last {
+			t.Fatalf("key %d reported after %d", key, last)
+		}
+		last = key
+		result = append(result, fmt.Sprint(key, kind))
+	})
+	sort.Strings(result)
+
+	if !isSameSlice(result, expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+}
+
+func TestPersistentConcurrentReaders(t *testing.T) {
+	var tr PersistentTree
+	for i := 0; i < 1000; i++ {
+		tr = tr.Insert(i, i)
+	}
+	snapshot := tr
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				if v, ok := snapshot.Get(i); !ok || v != i {
+					t.Errorf("expected %d, got %v", i, v)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 1000; i += 2 {
+		tr, _ = tr.Remove(i)
+		tr = tr.Insert(i+1, "changed")
+	}
+	wg.Wait()
+
+	if snapshot.Len() != 1000 || tr.Len() != 500 {
+		t.Fatalf("expected 1000 and 500 keys, got %d and %d", snapshot.Len(), tr.Len())
+	}
+}
+
+func BenchmarkDiff(b *testing.B) {
+	var older PersistentTree
+	for i := 0; i < 1<<16; i++ {
+		older = older.Insert(i, i)
+	}
+	newer := older.Insert(1<<15, "changed")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		older.Diff(newer, func(key int, kind DiffKind, oldValue, newValue interface{}) {})
+	}
+}
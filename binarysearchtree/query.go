@@ -0,0 +1,87 @@
+package binarysearchtree
+
+// Floor returns the node with the largest key less than
+// or equal to the specified key, or nil if there isn't one.
+func (n *Node) Floor(key int) *Node {
+	var floor *Node
+	for curr := n; curr != nil; {
+		switch {
+		case key < curr.Key:
+			curr = curr.Left
+		case key > curr.Key:
+			floor = curr
+			curr = curr.Right
+		default:
+			return curr
+		}
+	}
+	return floor
+}
+
+// Ceiling returns the node with the smallest key greater than
+// or equal to the specified key, or nil if there isn't one.
+func (n *Node) Ceiling(key int) *Node {
+	var ceiling *Node
+	for curr := n; curr != nil; {
+		switch {
+		case key < curr.Key:
+			ceiling = curr
+			curr = curr.Left
+		case key > curr.Key:
+			curr = curr.Right
+		default:
+			return curr
+		}
+	}
+	return ceiling
+}
+
+// Predecessor returns the node with the largest key strictly less
+// than the specified key, or nil if there isn't one. The key itself
+// doesn't need to be in the tree.
+func (n *Node) Predecessor(key int) *Node {
+	var pred *Node
+	for curr := n; curr != nil; {
+		if curr.Key < key {
+			pred = curr
+			curr = curr.Right
+		} else {
+			curr = curr.Left
+		}
+	}
+	return pred
+}
+
+// Successor returns the node with the smallest key strictly greater
+// than the specified key, or nil if there isn't one. The key itself
+// doesn't need to be in the tree.
+func (n *Node) Successor(key int) *Node {
+	var succ *Node
+	for curr := n; curr != nil; {
+		if curr.Key > key {
+			succ = curr
+			curr = curr.Left
+		} else {
+			curr = curr.Right
+		}
+	}
+	return succ
+}
+
+// Range calls Visitor in ascending order for each key between lo and
+// hi inclusive, skipping the subtrees which lie outside the bounds.
+func (n *Node) Range(lo, hi int, v Visitor) {
+	if n == nil {
+		return
+	}
+
+	if lo < n.Key {
+		n.Left.Range(lo, hi, v)
+	}
+	if lo <= n.Key && n.Key <= hi {
+		v(n.Key, n.Value)
+	}
+	if n.Key < hi {
+		n.Right.Range(lo, hi, v)
+	}
+}
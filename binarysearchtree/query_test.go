@@ -0,0 +1,127 @@
+package binarysearchtree
+
+import (
+	"fmt"
+	"testing"
+)
+
+// gapTree returns a tree holding the keys 10, 20, ... 110 so that
+// queries can fall between keys as well as on them.
+func gapTree() *Node {
+	root := &Node{Key: 80, Value: "80"}
+	for _, k := range []int{40, 100, 20, 60, 10, 30, 50, 70, 90, 110} {
+		root.Insert(k, fmt.Sprint(k))
+	}
+	return root
+}
+
+func TestOrderedQueries(t *testing.T) {
+	const none = -1
+
+	tests := map[string]struct {
+		key                    int
+		floor, ceiling         int
+		predecessor, successor int
+	}{
+		"BelowMin":    {5, none, 10, none, 10},
+		"Min":         {10, 10, 10, none, 20},
+		"AboveMin":    {15, 10, 20, 10, 20},
+		"Inner":       {50, 50, 50, 40, 60},
+		"BetweenLeaf": {55, 50, 60, 50, 60},
+		"Root":        {80, 80, 80, 70, 90},
+		"BelowRoot":   {75, 70, 80, 70, 80},
+		"AboveRoot":   {85, 80, 90, 80, 90},
+		"Max":         {110, 110, 110, 100, none},
+		"AboveMax":    {120, 110, none, 110, none},
+	}
+
+	key := func(n *Node) int {
+		if n == nil {
+			return none
+		}
+		return n.Key
+	}
+
+	root := gapTree()
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if k := key(root.Floor(test.key)); k != test.floor {
+				t.Errorf("floor: expected %d, got %d", test.floor, k)
+			}
+			if k := key(root.Ceiling(test.key)); k != test.ceiling {
+				t.Errorf("ceiling: expected %d, got %d", test.ceiling, k)
+			}
+			if k := key(root.Predecessor(test.key)); k != test.predecessor {
+				t.Errorf("predecessor: expected %d, got %d", test.predecessor, k)
+			}
+			if k := key(root.Successor(test.key)); k != test.successor {
+				t.Errorf("successor: expected %d, got %d", test.successor, k)
+			}
+		})
+	}
+}
+
+func TestOrderedQueriesEmpty(t *testing.T) {
+	var root *Node
+	if root.Floor(1) != nil || root.Ceiling(1) != nil || root.Predecessor(1) != nil || root.Successor(1) != nil {
+		t.Fatal("expected nil from an empty tree")
+	}
+
+	var tr Tree
+	if _, _, ok := tr.Floor(1); ok {
+		t.Fatal("expected no floor")
+	}
+	tr.Insert(1, "1")
+	if k, v, ok := tr.Floor(1); !ok || k != 1 || v != "1" {
+		t.Fatalf("expected 1, got %d %v", k, v)
+	}
+	if _, _, ok := tr.Successor(1); ok {
+		t.Fatal("expected no successor")
+	}
+}
+
+func TestRange(t *testing.T) {
+	tests := map[string]struct {
+		lo, hi   int
+		expected []string
+	}{
+		"All":       {0, 200, []string{"10", "20", "30", "40", "50", "60", "70", "80", "90", "100", "110"}},
+		"Inclusive": {20, 50, []string{"20", "30", "40", "50"}},
+		"Between":   {15, 55, []string{"20", "30", "40", "50"}},
+		"Single":    {80, 80, []string{"80"}},
+		"Gap":       {81, 89, nil},
+		"Below":     {0, 9, nil},
+		"Above":     {111, 200, nil},
+		"Reversed":  {50, 20, nil},
+		"Edges":     {10, 10, []string{"10"}},
+	}
+
+	root := gapTree()
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var result []string
+			root.Range(test.lo, test.hi, func(key int, value interface{}) {
+				result = append(result, value.(string))
+			})
+			if !isSameSlice(result, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestRangePrunes(t *testing.T) {
+	// Replace the subtrees outside the range with misplaced keys
+	// inside it, which are only found if Range visits them.
+	root := gapTree()
+	root.Left.Left = &Node{Key: 55, Value: "55"}
+	root.Right.Right = &Node{Key: 65, Value: "65"}
+
+	var result []string
+	root.Range(50, 80, func(key int, value interface{}) {
+		result = append(result, value.(string))
+	})
+	if expected := []string{"50", "60", "70", "80"}; !isSameSlice(result, expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+}
@@ -0,0 +1,85 @@
+package binarysearchtree
+
+// Rank returns the number of keys in the tree less than the specified
+// key, which doesn't need to be in the tree itself. Like Select,
+// CountRange and Median, it needs a tree built by Insert and Remove,
+// or one which has been recounted since it was built by hand.
+func (n *Node) Rank(key int) int {
+	return rank(n, key, false)
+}
+
+// rank counts the keys less than key, or less than
+// or equal to key if inclusive is set.
+func rank(n *Node, key int, inclusive bool) int {
+	r := 0
+	for curr := n; curr != nil; {
+		if key < curr.Key || (key == curr.Key && !inclusive) {
+			curr = curr.Left
+			continue
+		}
+
+		r += size(curr.Left) + 1
+		if key == curr.Key {
+			break
+		}
+		curr = curr.Right
+	}
+	return r
+}
+
+// Select returns the node with the kth smallest key, counting from
+// zero, or nil if k is out of range. Select(Rank(key)) is the node
+// holding key if it is in the tree. It needs a tree built by Insert
+// and Remove, or recounted since.
+func (n *Node) Select(k int) *Node {
+	for curr := n; curr != nil; {
+		l := size(curr.Left)
+		switch {
+		case k < l:
+			curr = curr.Left
+		case k > l:
+			k -= l + 1
+			curr = curr.Right
+		default:
+			return curr
+		}
+	}
+	return nil
+}
+
+// CountRange returns the number of keys between lo and hi inclusive.
+// It needs a tree built by Insert and Remove, or recounted since.
+func (n *Node) CountRange(lo, hi int) int {
+	if lo > hi {
+		return 0
+	}
+	return rank(n, hi, true) - rank(n, lo, false)
+}
+
+// Median returns the node with the median key, the lower
+// of the two middle keys when the tree has an even size. It needs a
+// tree built by Insert and Remove, or recounted since.
+func (n *Node) Median() *Node {
+	return n.Select((size(n) - 1) / 2)
+}
+
+// Recount recomputes the count of descendants each node keeps, so
+// that Rank, Select, CountRange and Median work on a tree built or
+// relinked by hand.
+func (n *Node) Recount() {
+	postOrder(n, func(curr *Node) bool {
+		updateSize(curr)
+		return true
+	})
+}
+
+func size(n *Node) int {
+	if n == nil {
+		return 0
+	}
+	return n.desc + 1
+}
+
+func updateSize(n *Node) {
+	n.desc = size(n.Left) + size(n.Right)
+}
@@ -0,0 +1,201 @@
+package binarysearchtree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// checkSizes returns the size of the subtree rooted at n,
+// failing if any node below it holds the wrong count.
+func checkSizes(t *testing.T, n *Node) int {
+	t.Helper()
+	if n == nil {
+		return 0
+	}
+
+	desc := checkSizes(t, n.Left) + checkSizes(t, n.Right)
+	if n.desc != desc {
+		t.Fatalf("key %d has %d descendants, expected %d", n.Key, n.desc, desc)
+	}
+	return desc + 1
+}
+
+func TestRank(t *testing.T) {
+	tests := map[string]struct {
+		key  int
+		rank int
+	}{
+		"BelowMin": {5, 0},
+		"Min":      {10, 0},
+		"Between":  {15, 1},
+		"Root":     {80, 7},
+		"Max":      {110, 10},
+		"AboveMax": {120, 11},
+	}
+
+	root := gapTree()
+	checkSizes(t, root)
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if r := root.Rank(test.key); r != test.rank {
+				t.Fatalf("expected %d, got %d", test.rank, r)
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	root := gapTree()
+	for k := 0; k < 11; k++ {
+		n := root.Select(k)
+		if n == nil || n.Key != (k+1)*10 {
+			t.Fatalf("select %d: expected %d, got %v", k, (k+1)*10, n)
+		}
+		if r := root.Rank(n.Key); r != k {
+			t.Fatalf("rank %d: expected %d, got %d", n.Key, k, r)
+		}
+	}
+
+	if root.Select(-1) != nil || root.Select(11) != nil {
+		t.Fatal("expected nil for out of range selects")
+	}
+}
+
+func TestCountRange(t *testing.T) {
+	tests := map[string]struct {
+		lo, hi int
+		count  int
+	}{
+		"All":       {0, 200, 11},
+		"Inclusive": {20, 50, 4},
+		"Between":   {15, 55, 4},
+		"Single":    {80, 80, 1},
+		"Gap":       {81, 89, 0},
+		"Reversed":  {50, 20, 0},
+	}
+
+	root := gapTree()
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if c := root.CountRange(test.lo, test.hi); c != test.count {
+				t.Fatalf("expected %d, got %d", test.count, c)
+			}
+		})
+	}
+}
+
+func TestMedian(t *testing.T) {
+	var tr Tree
+	if _, _, ok := tr.Median(); ok {
+		t.Fatal("expected no median")
+	}
+
+	for i, expected := range []int{1, 1, 2, 2, 3} {
+		tr.Insert(i+1, nil)
+		if k, _, _ := tr.Median(); k != expected {
+			t.Fatalf("%d keys: expected %d, got %d", i+1, expected, k)
+		}
+	}
+}
+
+func TestRecount(t *testing.T) {
+	// A literal tree starts with every count at zero.
+	root := &Node{Key: 2, Left: &Node{Key: 1}, Right: &Node{Key: 3}}
+	if err := validateSizes(root); err == nil {
+		t.Fatal("expected a literal tree to have stale counts")
+	}
+
+	root.Recount()
+	checkSizes(t, root)
+	if m := root.Median(); m == nil || m.Key != 2 {
+		t.Fatalf("expected median 2, got %v", m)
+	}
+	if r := root.Rank(3); r != 2 {
+		t.Fatalf("expected rank 2, got %d", r)
+	}
+	if n := root.Select(2); n == nil || n.Key != 3 {
+		t.Fatalf("expected 3, got %v", n)
+	}
+	if c := root.CountRange(1, 3); c != 3 {
+		t.Fatalf("expected 3, got %d", c)
+	}
+
+	// Relinking by hand leaves the counts stale again.
+	root.Right.Right = &Node{Key: 4}
+	root.Recount()
+	checkSizes(t, root)
+	if r := root.Rank(5); r != 4 {
+		t.Fatalf("expected rank 4, got %d", r)
+	}
+
+	var empty *Node
+	empty.Recount()
+}
+
+func TestOrderStatistics(t *testing.T) {
+	type statTree interface {
+		OrderedMap
+		Rank(key int) int
+		Select(k int) (int, interface{}, bool)
+		CountRange(lo, hi int) int
+	}
+
+	for _, m := range []struct {
+		name string
+		tree statTree
+	}{
+		{"Node", &Tree{}},
+		{"AVL", &AVLTree{}},
+		{"RB", &RBTree{}},
+	} {
+		t.Run(m.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			model := make(map[int]bool)
+
+			for i := 0; i < 2000; i++ {
+				key := r.Intn(500)
+				if r.Intn(3) == 0 {
+					m.tree.Delete(key)
+					delete(model, key)
+				} else {
+					m.tree.Insert(key, key)
+					model[key] = true
+				}
+			}
+
+			var keys []int
+			for k := range model {
+				keys = append(keys, k)
+			}
+			sort.Ints(keys)
+
+			switch tr := m.tree.(type) {
+			case *Tree:
+				checkSizes(t, tr.root)
+			case *AVLTree:
+				checkSizes(t, tr.root)
+			case *RBTree:
+				checkSizes(t, tr.root)
+			}
+
+			for i, k := range keys {
+				if r := m.tree.Rank(k); r != i {
+					t.Fatalf("rank %d: expected %d, got %d", k, i, r)
+				}
+				if s, _, _ := m.tree.Select(i); s != k {
+					t.Fatalf("select %d: expected %d, got %d", i, k, s)
+				}
+			}
+
+			lo, hi := 100, 300
+			want := sort.SearchInts(keys, hi+1) - sort.SearchInts(keys, lo)
+			if c := m.tree.CountRange(lo, hi); c != want {
+				t.Fatalf("count %d-%d: expected %d, got %d", lo, hi, want, c)
+			}
+			if m.tree.Len() != len(keys) {
+				t.Fatalf("expected %d keys, got %d", len(keys), m.tree.Len())
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package binarysearchtree
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RBTree is a left-leaning red-black tree. Every path from the root
+// to a leaf holds the same number of black nodes and red nodes are
+// only ever left children of black nodes, so the height of the tree
+// is at most 2 log n, a looser bound than AVLTree keeps.
+type RBTree struct {
+	tree
+}
+
+// Insert adds the given key and value to the tree, replacing
+// the value if the key already exists.
+func (t *RBTree) Insert(key int, value interface{}) {
+	var added bool
+	t.root = rbInsert(t.root, key, value, &added)
+	t.root.red = false
+	if added {
+		t.size++
+	}
+}
+
+func rbInsert(n *Node, key int, value interface{}, added *bool) *Node {
+	if n == nil {
+		*added = true
+		return &Node{Key: key, Value: value, red: true}
+	}
+
+	switch {
+	case key < n.Key:
+		n.Left = rbInsert(n.Left, key, value, added)
+	case key > n.Key:
+		n.Right = rbInsert(n.Right, key, value, added)
+	default:
+		n.Value = value
+	}
+	return rbBalance(n)
+}
+
+// Delete removes the key from the tree and returns
+// the value it was associated with.
+func (t *RBTree) Delete(key int) (interface{}, bool) {
+	// rbRemove expects the key to be present.
+	n := t.root.Exact(key)
+	if n == nil {
+		return nil, false
+	}
+
+	// rbRemove may copy another node into n, so take the value first.
+	value := n.Value
+	if !isRed(t.root.Left) && !isRed(t.root.Right) {
+		t.root.red = true
+	}
+	t.root = rbRemove(t.root, key)
+	if t.root != nil {
+		t.root.red = false
+	}
+	t.size--
+	return value, true
+}
+
+func rbRemove(n *Node, key int) *Node {
+	if key < n.Key {
+		if !isRed(n.Left) && !isRed(n.Left.Left) {
+			n = moveRedLeft(n)
+		}
+		n.Left = rbRemove(n.Left, key)
+		return rbBalance(n)
+	}
+
+	if isRed(n.Left) {
+		n = rbRotateRight(n)
+	}
+	if key == n.Key && n.Right == nil {
+		return nil
+	}
+	if !isRed(n.Right) && !isRed(n.Right.Left) {
+		n = moveRedRight(n)
+	}
+	if key == n.Key {
+		smallestRight := n.Right.Min()
+		n.Key, n.Value = smallestRight.Key, smallestRight.Value
+		n.Right = rbRemoveMin(n.Right)
+	} else {
+		n.Right = rbRemove(n.Right, key)
+	}
+	return rbBalance(n)
+}
+
+func rbRemoveMin(n *Node) *Node {
+	if n.Left == nil {
+		return nil
+	}
+	if !isRed(n.Left) && !isRed(n.Left.Left) {
+		n = moveRedLeft(n)
+	}
+	n.Left = rbRemoveMin(n.Left)
+	return rbBalance(n)
+}
+
+// Validate returns an error if the tree is not ordered, a stored size
+// is wrong, a red node is a right child or has a red child, or the
+// paths from the root to the leaves hold different numbers of black
+// nodes.
+func (t *RBTree) Validate() error {
+	if isRed(t.root) {
+		return errors.New("root is red")
+	}
+
+	_, count, err := validateRB(t.root, nil, nil)
+	if err != nil {
+		return err
+	}
+	if count != t.size {
+		return fmt.Errorf("expected %d nodes, counted %d", t.size, count)
+	}
+	return nil
+}
+
+// validateRB checks the subtree rooted at n, whose keys must lie
+// between lo and hi, and returns its black height and node count.
+func validateRB(n *Node, lo, hi *int) (int, int, error) {
+	if n == nil {
+		return 1, 0, nil
+	}
+	if (lo != nil && n.Key <= *lo) || (hi != nil && n.Key >= *hi) {
+		return 0, 0, fmt.Errorf("key %d is out of order", n.Key)
+	}
+	if isRed(n.Right) {
+		return 0, 0, fmt.Errorf("key %d has a red right child", n.Key)
+	}
+	if isRed(n) && isRed(n.Left) {
+		return 0, 0, fmt.Errorf("key %d is red with a red child", n.Key)
+	}
+
+	lb, lc, err := validateRB(n.Left, lo, &n.Key)
+	if err != nil {
+		return 0, 0, err
+	}
+	rb, rc, err := validateRB(n.Right, &n.Key, hi)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if lb != rb {
+		return 0, 0, fmt.Errorf("key %d has black heights %d and %d", n.Key, lb, rb)
+	}
+	if n.desc != lc+rc {
+		return 0, 0, fmt.Errorf("key %d has %d descendants, expected %d", n.Key, n.desc, lc+rc)
+	}
+	if !isRed(n) {
+		lb++
+	}
+	return lb, lc + rc + 1, nil
+}
+
+func isRed(n *Node) bool {
+	return n != nil && n.red
+}
+
+func rbRotateLeft(n *Node) *Node {
+	r := n.Right
+	n.Right = r.Left
+	r.Left = n
+	r.red = n.red
+	n.red = true
+	updateSize(n)
+	updateSize(r)
+	return r
+}
+
+func rbRotateRight(n *Node) *Node {
+	l := n.Left
+	n.Left = l.Right
+	l.Right = n
+	l.red = n.red
+	n.red = true
+	updateSize(n)
+	updateSize(l)
+	return l
+}
+
+func flipColors(n *Node) {
+	n.red = !n.red
+	n.Left.red = !n.Left.red
+	n.Right.red = !n.Right.red
+}
+
+// moveRedLeft makes n.Left or one of its children red,
+// assuming n is red and both its children are black.
+func moveRedLeft(n *Node) *Node {
+	flipColors(n)
+	if isRed(n.Right.Left) {
+		n.Right = rbRotateRight(n.Right)
+		n = rbRotateLeft(n)
+		flipColors(n)
+	}
+	return n
+}
+
+// moveRedRight makes n.Right or one of its children red,
+// assuming n is red and both its children are black.
+func moveRedRight(n *Node) *Node {
+	flipColors(n)
+	if isRed(n.Left.Left) {
+		n = rbRotateRight(n)
+		flipColors(n)
+	}
+	return n
+}
+
+// rbBalance restores the left-leaning invariants on the way up.
+func rbBalance(n *Node) *Node {
+	updateSize(n)
+	if isRed(n.Right) && !isRed(n.Left) {
+		n = rbRotateLeft(n)
+	}
+	if isRed(n.Left) && isRed(n.Left.Left) {
+		n = rbRotateRight(n)
+	}
+	if isRed(n.Left) && isRed(n.Right) {
+		flipColors(n)
+	}
+	return n
+}
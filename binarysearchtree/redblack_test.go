@@ -0,0 +1,255 @@
+package binarysearchtree
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestRBInsert(t *testing.T) {
+	tests := map[string]struct {
+		keys     []int
+		expected []string
+	}{
+		"Ascending":  {[]int{1, 2, 3}, []string{"2", "1", "3"}},
+		"Descending": {[]int{3, 2, 1}, []string{"2", "1", "3"}},
+		"Zigzag":     {[]int{1, 3, 2}, []string{"2", "1", "3"}},
+		"LeansLeft":  {[]int{1, 2}, []string{"2", "1"}},
+		"Replace":    {[]int{2, 1, 2}, []string{"2", "1"}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var tr RBTree
+			for _, k := range test.keys {
+				tr.Insert(k, k)
+			}
+
+			if err := tr.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if result := preOrder(&tr); !isSameSlice(result, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, result)
+			}
+			if tr.Len() != len(test.expected) {
+				t.Fatalf("expected len %d, got %d", len(test.expected), tr.Len())
+			}
+		})
+	}
+}
+
+func TestRBDelete(t *testing.T) {
+	tests := map[string]struct {
+		deletes  []int
+		expected []string
+	}{
+		"Leaf":    {[]int{1}, []string{"2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}},
+		"Inner":   {[]int{4}, []string{"1", "2", "3", "5", "6", "7", "8", "9", "10", "11"}},
+		"Root":    {[]int{8}, []string{"1", "2", "3", "4", "5", "6", "7", "9", "10", "11"}},
+		"Missing": {[]int{12}, []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}},
+		"All":     {[]int{8, 4, 10, 2, 6, 1, 3, 5, 7, 9, 11}, nil},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var tr RBTree
+			for _, k := range []int{8, 4, 10, 2, 6, 1, 3, 5, 7, 9, 11} {
+				tr.Insert(k, fmt.Sprint(k))
+			}
+
+			for _, k := range test.deletes {
+				v, ok := tr.Delete(k)
+				if ok != (k <= 11) || (ok && v != fmt.Sprint(k)) {
+					t.Fatalf("delete %d: got %v %v", k, v, ok)
+				}
+				if err := tr.Validate(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if result := inOrder(&tr); !isSameSlice(result, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, result)
+			}
+			if tr.Len() != len(test.expected) {
+				t.Fatalf("expected len %d, got %d", len(test.expected), tr.Len())
+			}
+		})
+	}
+}
+
+func TestRBSequential(t *testing.T) {
+	var tr RBTree
+	for i := 0; i < 1<<16; i++ {
+		tr.Insert(i, i)
+	}
+
+	if err := tr.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if max := int(2 * math.Log2(float64(tr.Len()+1))); tr.Height() > max {
+		t.Fatalf("expected height at most %d, got %d", max, tr.Height())
+	}
+	if v, ok := tr.Get(1000); !ok || v != 1000 {
+		t.Fatalf("expected 1000, got %v", v)
+	}
+	if k, _, _ := tr.Min(); k != 0 {
+		t.Fatalf("expected min 0, got %d", k)
+	}
+	if k, _, _ := tr.Max(); k != 1<<16-1 {
+		t.Fatalf("expected max %d, got %d", 1<<16-1, k)
+	}
+
+	for i := 1<<16 - 1; i >= 0; i -= 2 {
+		tr.Delete(i)
+	}
+	if err := tr.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if tr.Len() != 1<<15 {
+		t.Fatalf("expected %d, got %d", 1<<15, tr.Len())
+	}
+}
+
+func TestRBValidate(t *testing.T) {
+	var tr RBTree
+	for _, k := range []int{2, 1, 3} {
+		tr.Insert(k, k)
+	}
+
+	tr.root.Left.Key = 4
+	if tr.Validate() == nil {
+		t.Fatal("expected ordering error")
+	}
+	tr.root.Left.Key = 1
+
+	tr.root.Right.red = true
+	if tr.Validate() == nil {
+		t.Fatal("expected right leaning error")
+	}
+	tr.root.Right.red = false
+
+	tr.root.Left.red = true
+	tr.root.Left.Left = &Node{Key: 0, red: true}
+	tr.size++
+	if tr.Validate() == nil {
+		t.Fatal("expected red-red error")
+	}
+
+	tr.root.Left.red = false
+	tr.root.Left.Left.red = false
+	if tr.Validate() == nil {
+		t.Fatal("expected black height error")
+	}
+}
+
+// FuzzRB interprets the input in the same way as FuzzAVL.
+func FuzzRB(f *testing.F) {
+	f.Add([]byte{0, 8, 0, 4, 0, 10, 0, 2, 0, 6, 0, 1, 0, 3, 0, 5, 0, 7, 0, 9, 0, 11})
+	f.Add([]byte{0, 8, 0, 4, 0, 10, 1, 8, 1, 4, 1, 10})
+	f.Add([]byte{0, 1, 0, 2, 0, 3, 0, 4, 0, 5, 1, 1, 1, 3})
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		var tr RBTree
+		model := make(map[int]int)
+
+		for i := 0; i+1 < len(ops); i += 2 {
+			key := int(ops[i+1])
+			if ops[i]%2 == 0 {
+				tr.Insert(key, i)
+				model[key] = i
+			} else {
+				v, ok := tr.Delete(key)
+				mv, mok := model[key]
+				if ok != mok || (ok && v != mv) {
+					t.Fatalf("delete %d: expected %v %v, got %v %v", key, mv, mok, v, ok)
+				}
+				delete(model, key)
+			}
+
+			if err := tr.Validate(); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var keys []int
+		for k := range model {
+			keys = append(keys, k)
+		}
+		sort.Ints(keys)
+
+		var got []int
+		tr.InOrderTraverse(func(key int, value interface{}) {
+			if value != model[key] {
+				t.Fatalf("%d: expected %d, got %v", key, model[key], value)
+			}
+			got = append(got, key)
+		})
+		if fmt.Sprint(got) != fmt.Sprint(keys) {
+			t.Fatalf("expected %v, got %v", keys, got)
+		}
+	})
+}
+
+var orderedMaps = []struct {
+	name string
+	new  func() OrderedMap
+}{
+	{"Node", func() OrderedMap { return &Tree{} }},
+	{"AVL", func() OrderedMap { return &AVLTree{} }},
+	{"RB", func() OrderedMap { return &RBTree{} }},
+	{"Splay", func() OrderedMap { return &SplayTree{} }},
+}
+
+// benchmarkKeys returns the keys inserted by the comparative benchmarks.
+// The unbalanced tree degrades to a list on sequential keys, so n is
+// kept small enough for it to finish.
+func benchmarkKeys(random bool) []int {
+	const n = 1 << 10
+	if random {
+		return rand.New(rand.NewSource(1)).Perm(n)
+	}
+
+	keys := make([]int, n)
+	for i := range keys {
+		keys[i] = i
+	}
+	return keys
+}
+
+func BenchmarkInsert(b *testing.B) {
+	for _, pattern := range []string{"Random", "Sequential"} {
+		keys := benchmarkKeys(pattern == "Random")
+
+		for _, m := range orderedMaps {
+			b.Run(pattern+"/"+m.name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					tr := m.new()
+					for _, k := range keys {
+						tr.Insert(k, k)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	for _, pattern := range []string{"Random", "Sequential"} {
+		keys := benchmarkKeys(pattern == "Random")
+
+		for _, m := range orderedMaps {
+			tr := m.new()
+			for _, k := range keys {
+				tr.Insert(k, k)
+			}
+
+			b.Run(pattern+"/"+m.name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					tr.Get(keys[i%len(keys)])
+				}
+			})
+		}
+	}
+}
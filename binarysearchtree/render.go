@@ -0,0 +1,213 @@
+package binarysearchtree
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// String returns the sideways view of the tree written by WriteIndented.
+func (n *Node) String() string {
+	var b strings.Builder
+	stringify(&b, n, 0)
+	return b.String()
+}
+
+// WriteIndented writes the tree sideways, one key per line in ascending
+// order, indenting each key by a tab for every level below the root.
+func (n *Node) WriteIndented(w io.Writer) error {
+	_, err := io.WriteString(w, n.String())
+	return err
+}
+
+func stringify(b *strings.Builder, n *Node, level int) {
+	if n == nil {
+		return
+	}
+
+	stringify(b, n.Left, level+1)
+	b.WriteString(strings.Repeat("\t", level))
+	b.WriteString(strconv.Itoa(n.Key))
+	b.WriteByte('\n')
+	stringify(b, n.Right, level+1)
+}
+
+// WriteASCII draws the tree from the root down, for example
+//
+//	  _4
+//	 /  \
+//	 2  6
+//	/ \
+//	1 3
+func (n *Node) WriteASCII(w io.Writer) error {
+	if n == nil {
+		return nil
+	}
+
+	var b strings.Builder
+	lines, _, _ := drawASCII(n)
+	for _, line := range lines {
+		b.WriteString(strings.TrimRight(line, " "))
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// drawASCII returns the lines of the drawing of the subtree rooted
+// at n, all of the same width, and the column its root is centred on.
+func drawASCII(n *Node) ([]string, int, int) {
+	label := strconv.Itoa(n.Key)
+	u := len(label)
+
+	switch {
+	case n.Left == nil && n.Right == nil:
+		return []string{label}, u, u / 2
+
+	case n.Right == nil:
+		lines, width, mid := drawASCII(n.Left)
+		first := pad(mid+1) + strings.Repeat("_", width-mid-1) + label
+		second := pad(mid) + "/" + pad(width-mid-1+u)
+		for i := range lines {
+			lines[i] += pad(u)
+		}
+		return append([]string{first, second}, lines...), width + u, width + u/2
+
+	case n.Left == nil:
+		lines, width, mid := drawASCII(n.Right)
+		first := label + strings.Repeat("_", mid) + pad(width-mid)
+		second := pad(u+mid) + `\` + pad(width-mid-1)
+		for i := range lines {
+			lines[i] = pad(u) + lines[i]
+		}
+		return append([]string{first, second}, lines...), width + u, u / 2
+	}
+
+	left, lw, lm := drawASCII(n.Left)
+	right, rw, rm := drawASCII(n.Right)
+	first := pad(lm+1) + strings.Repeat("_", lw-lm-1) + label + strings.Repeat("_", rm) + pad(rw-rm)
+	second := pad(lm) + "/" + pad(lw-lm-1+u+rm) + `\` + pad(rw-rm-1)
+
+	for len(left) < len(right) {
+		left = append(left, pad(lw))
+	}
+	for len(right) < len(left) {
+		right = append(right, pad(rw))
+	}
+
+	lines := []string{first, second}
+	for i := range left {
+		lines = append(lines, left[i]+pad(u)+right[i])
+	}
+	return lines, lw + rw + u, lw + u/2
+}
+
+func pad(n int) string {
+	return strings.Repeat(" ", n)
+}
+
+// DOTOptions controls the output of WriteDOT.
+type DOTOptions struct {
+	// Values adds each node's value to its label.
+	Values bool
+	// Label, if set, replaces the label of each node.
+	Label func(n *Node) string
+	// Color, if set, returns the colour to draw each node in.
+	Color func(n *Node) string
+}
+
+// WriteDOT writes the tree in the Graphviz DOT language. A node with
+// only one child is given an invisible sibling, so that the child is
+// still drawn on the correct side.
+func (n *Node) WriteDOT(w io.Writer, opts DOTOptions) error {
+	var b strings.Builder
+	b.WriteString("digraph bst {\n")
+	b.WriteString("\tnode [shape=circle];\n")
+
+	id := 0
+	var write func(n *Node) int
+	write = func(n *Node) int {
+		self := id
+		id++
+
+		label := nodeLabel(n, opts.Values)
+		if opts.Label != nil {
+			label = opts.Label(n)
+		}
+		fmt.Fprintf(&b, "\tn%d [label=%s", self, strconv.Quote(label))
+		if opts.Color != nil {
+			c := strconv.Quote(opts.Color(n))
+			fmt.Fprintf(&b, ", color=%s, fontcolor=%s", c, c)
+		}
+		b.WriteString("];\n")
+
+		if n.Left == nil && n.Right == nil {
+			return self
+		}
+		for _, child := range []*Node{n.Left, n.Right} {
+			if child == nil {
+				fmt.Fprintf(&b, "\tn%d [shape=point, style=invis];\n", id)
+				fmt.Fprintf(&b, "\tn%d -> n%d [style=invis];\n", self, id)
+				id++
+				continue
+			}
+			fmt.Fprintf(&b, "\tn%d -> n%d;\n", self, write(child))
+		}
+		return self
+	}
+	if n != nil {
+		write(n)
+	}
+
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func nodeLabel(n *Node, values bool) string {
+	if values {
+		return fmt.Sprintf("%d: %v", n.Key, n.Value)
+	}
+	return strconv.Itoa(n.Key)
+}
+
+// WriteIndented writes the tree sideways, one key per line.
+func (t *tree) WriteIndented(w io.Writer) error {
+	return t.root.WriteIndented(w)
+}
+
+// WriteASCII draws the tree from the root down.
+func (t *tree) WriteASCII(w io.Writer) error {
+	return t.root.WriteASCII(w)
+}
+
+// WriteDOT writes the tree in the Graphviz DOT language.
+func (t *tree) WriteDOT(w io.Writer, opts DOTOptions) error {
+	return t.root.WriteDOT(w, opts)
+}
+
+// WriteDOT writes the tree in the Graphviz DOT language,
+// adding the height of each node to its label by default.
+func (t *AVLTree) WriteDOT(w io.Writer, opts DOTOptions) error {
+	if opts.Label == nil {
+		opts.Label = func(n *Node) string {
+			return fmt.Sprintf("%s\nh=%d", nodeLabel(n, opts.Values), n.height)
+		}
+	}
+	return t.root.WriteDOT(w, opts)
+}
+
+// WriteDOT writes the tree in the Graphviz DOT
+// language, drawing each node in its colour by default.
+func (t *RBTree) WriteDOT(w io.Writer, opts DOTOptions) error {
+	if opts.Color == nil {
+		opts.Color = func(n *Node) string {
+			if n.red {
+				return "red"
+			}
+			return "black"
+		}
+	}
+	return t.root.WriteDOT(w, opts)
+}
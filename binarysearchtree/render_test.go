@@ -0,0 +1,105 @@
+package binarysearchtree
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// golden compares the output with testdata/name.golden.
+func golden(t *testing.T, name string, write func(w io.Writer) error) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Fatalf("%s: expected\n%s\ngot\n%s", path, expected, buf.Bytes())
+	}
+}
+
+func TestStringer(t *testing.T) {
+	var _ fmt.Stringer = &Node{}
+
+	root := &Node{Key: 2}
+	root.Insert(1, nil)
+	root.Insert(3, nil)
+	if s := root.String(); s != "\t1\n2\n\t3\n" {
+		t.Fatalf("unexpected string %q", s)
+	}
+
+	var empty *Node
+	if s := empty.String(); s != "" {
+		t.Fatalf("expected an empty string, got %q", s)
+	}
+}
+
+func TestRender(t *testing.T) {
+	root := &Node{Key: 8, Value: "8"}
+	fillTree(root)
+
+	// A tree with one sided nodes on both sides and multi digit keys.
+	lopsided := &Node{Key: 50, Value: "50"}
+	for _, k := range []int{20, 10, 15, 70, 90, 80, 100, 1000} {
+		lopsided.Insert(k, fmt.Sprint(k))
+	}
+
+	var avl AVLTree
+	var rb RBTree
+	for i := 1; i <= 10; i++ {
+		avl.Insert(i, i)
+		rb.Insert(i, i)
+	}
+
+	tests := map[string]func(w io.Writer) error{
+		"indented":       root.WriteIndented,
+		"ascii":          root.WriteASCII,
+		"ascii_lopsided": lopsided.WriteASCII,
+		"dot":            func(w io.Writer) error { return root.WriteDOT(w, DOTOptions{}) },
+		"dot_values":     func(w io.Writer) error { return lopsided.WriteDOT(w, DOTOptions{Values: true}) },
+		"dot_avl":        func(w io.Writer) error { return avl.WriteDOT(w, DOTOptions{}) },
+		"dot_rb":         func(w io.Writer) error { return rb.WriteDOT(w, DOTOptions{}) },
+	}
+
+	for name, write := range tests {
+		t.Run(name, func(t *testing.T) {
+			golden(t, name, write)
+		})
+	}
+}
+
+func TestRenderEmpty(t *testing.T) {
+	var tr Tree
+	var buf bytes.Buffer
+	if err := tr.WriteASCII(&buf); err != nil || buf.Len() != 0 {
+		t.Fatalf("expected no output, got %q", buf.String())
+	}
+	if err := tr.WriteIndented(&buf); err != nil || buf.Len() != 0 {
+		t.Fatalf("expected no output, got %q", buf.String())
+	}
+	if err := tr.WriteDOT(&buf, DOTOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "digraph bst {\n\tnode [shape=circle];\n}\n"; buf.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,190 @@
+package binarysearchtree
+
+// SplayTree is a self-adjusting binary search tree. Every access
+// splays the key it looks for to the root, so recently used keys stay
+// near the top and are quick to find again. A single operation can
+// take O(n), as inserting keys in order leaves the tree a chain, but
+// any sequence of m operations takes O(m log n) in all.
+//
+// As every lookup rewrites part of the tree, it is slower than AVLTree
+// unless a handful of keys account for most lookups, or keys are
+// looked up in order, when it is quicker. BenchmarkAccessPattern
+// compares them.
+//
+// As Get and Search restructure the tree, a SplayTree isn't safe for
+// concurrent use even if it is only being read. The other read methods
+// don't splay.
+type SplayTree struct {
+	tree
+}
+
+// Insert adds the given key and value to the tree, replacing
+// the value if the key already exists. The key is left at the root.
+func (t *SplayTree) Insert(key int, value interface{}) {
+	if t.root == nil {
+		t.root = &Node{Key: key, Value: value}
+		t.size++
+		return
+	}
+
+	root := splay(t.root, key)
+	if root.Key == key {
+		root.Value = value
+		t.root = root
+		return
+	}
+
+	// Split the tree around the new key, which the splay left
+	// between the root and one of its children.
+	n := &Node{Key: key, Value: value}
+	if key < root.Key {
+		n.Left, n.Right = root.Left, root
+		root.Left = nil
+	} else {
+		n.Left, n.Right = root, root.Right
+		root.Right = nil
+	}
+	updateSize(root)
+	updateSize(n)
+	t.root = n
+	t.size++
+}
+
+// Get returns the value associated with the key,
+// which is splayed to the root if it is found.
+func (t *SplayTree) Get(key int) (interface{}, bool) {
+	if t.root == nil {
+		return nil, false
+	}
+
+	t.root = splay(t.root, key)
+	if t.root.Key != key {
+		return nil, false
+	}
+	return t.root.Value, true
+}
+
+// Search returns true if the given key is found within the tree.
+func (t *SplayTree) Search(key int) bool {
+	_, ok := t.Get(key)
+	return ok
+}
+
+// Delete removes the key from the tree and returns
+// the value it was associated with.
+func (t *SplayTree) Delete(key int) (interface{}, bool) {
+	if t.root == nil {
+		return nil, false
+	}
+
+	root := splay(t.root, key)
+	t.root = root
+	if root.Key != key {
+		return nil, false
+	}
+
+	if root.Left == nil {
+		t.root = root.Right
+	} else {
+		// Every key on the left is less than key, so splaying it
+		// brings the max to the top, leaving its right empty.
+		t.root = splay(root.Left, key)
+		t.root.Right = root.Right
+		updateSize(t.root)
+	}
+	t.size--
+	return root.Value, true
+}
+
+// splay moves the node holding key to the root of the tree, or if
+// it isn't in the tree the last node on the search path for it, and
+// returns the new root. It works top down: walking from the root, the
+// nodes passed on the way are hung in order from a left tree of smaller
+// keys and a right tree of larger ones, which become the children of
+// the node it stops at, with a rotation whenever it takes two steps the
+// same way.
+func splay(n *Node, key int) *Node {
+	// header.Right is the root of the left tree and header.Left the
+	// root of the right tree. l is the max of the left tree, and r
+	// the min of the right tree, where the next nodes are hung.
+	var header Node
+	l, r := &header, &header
+	// The number of nodes in each tree, not counting those
+	// which will be hung below l and r at the end.
+	var leftSize, rightSize int
+
+	for {
+		if key < n.Key {
+			if n.Left == nil {
+				break
+			}
+			if key < n.Left.Key {
+				// Zig-zig: rotate right before linking.
+				c := n.Left
+				n.Left = c.Right
+				c.Right = n
+				updateSize(n)
+				n = c
+				if n.Left == nil {
+					break
+				}
+			}
+			r.Left = n
+			r = n
+			rightSize += size(n.Right) + 1
+			n = n.Left
+		} else if key > n.Key {
+			if n.Right == nil {
+				break
+			}
+			if key > n.Right.Key {
+				// Zag-zag: rotate left before linking.
+				c := n.Right
+				n.Right = c.Left
+				c.Left = n
+				updateSize(n)
+				n = c
+				if n.Right == nil {
+					break
+				}
+			}
+			l.Right = n
+			l = n
+			leftSize += size(n.Left) + 1
+			n = n.Right
+		} else {
+			break
+		}
+	}
+
+	l.Right, r.Left = n.Left, n.Right
+	leftSize += size(n.Left)
+	rightSize += size(n.Right)
+
+	// The sizes of the nodes hung from the trees are only known now.
+	// Each one's subtree is the rest of the tree below it, so walking
+	// down from the top of each tree takes off one node and the subtree
+	// it didn't hang from at each step.
+	if l != &header {
+		for x, s := header.Right, leftSize; ; x = x.Right {
+			x.desc = s - 1
+			if x == l {
+				break
+			}
+			s -= size(x.Left) + 1
+		}
+	}
+	if r != &header {
+		for x, s := header.Left, rightSize; ; x = x.Left {
+			x.desc = s - 1
+			if x == r {
+				break
+			}
+			s -= size(x.Right) + 1
+		}
+	}
+
+	n.Left, n.Right = header.Right, header.Left
+	updateSize(n)
+	return n
+}
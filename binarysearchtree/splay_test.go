@@ -0,0 +1,216 @@
+package binarysearchtree
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestSplayAccess(t *testing.T) {
+	var tr SplayTree
+	for _, k := range []int{50, 30, 70, 20, 40, 60, 80} {
+		tr.Insert(k, fmt.Sprint(k))
+		if tr.root.Key != k {
+			t.Fatalf("expected %d at the root after insert, got %d", k, tr.root.Key)
+		}
+	}
+
+	tests := map[string]struct {
+		key   int
+		found bool
+	}{
+		"Min":         {20, true},
+		"Max":         {80, true},
+		"Inner":       {40, true},
+		"MissingLow":  {10, false},
+		"MissingHigh": {90, false},
+		"MissingGap":  {45, false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			v, ok := tr.Get(test.key)
+			if ok != test.found || (ok && v != fmt.Sprint(test.key)) {
+				t.Fatalf("expected %v, got %v %v", test.found, v, ok)
+			}
+			// A missing key leaves one of its neighbours at the root.
+			floor, _, _ := tr.Floor(test.key)
+			ceiling, _, _ := tr.Ceiling(test.key)
+			if root := tr.root.Key; root != floor && root != ceiling {
+				t.Fatalf("expected %d or %d at the root, got %d", floor, ceiling, root)
+			}
+			if err := tr.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if tr.Len() != 7 {
+				t.Fatalf("expected 7 keys, got %d", tr.Len())
+			}
+		})
+	}
+}
+
+func TestSplayDelete(t *testing.T) {
+	var tr SplayTree
+	if _, ok := tr.Delete(1); ok {
+		t.Fatal("expected nothing to delete")
+	}
+
+	for _, k := range []int{5, 3, 8, 1, 4, 7, 9} {
+		tr.Insert(k, fmt.Sprint(k))
+	}
+	for _, test := range []struct {
+		key  int
+		ok   bool
+		keys []string
+	}{
+		{6, false, []string{"1", "3", "4", "5", "7", "8", "9"}},
+		{5, true, []string{"1", "3", "4", "7", "8", "9"}},
+		{1, true, []string{"3", "4", "7", "8", "9"}},
+		{9, true, []string{"3", "4", "7", "8"}},
+		{3, true, []string{"4", "7", "8"}},
+	} {
+		v, ok := tr.Delete(test.key)
+		if ok != test.ok || (ok && v != fmt.Sprint(test.key)) {
+			t.Fatalf("delete %d: expected %v, got %v %v", test.key, test.ok, v, ok)
+		}
+		if result := inOrder(&tr); !isSameSlice(result, test.keys) {
+			t.Fatalf("delete %d: expected %v, got %v", test.key, test.keys, result)
+		}
+		if err := tr.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestSplayLocality checks that repeated access to a few keys of a
+// degenerate tree brings them, and keeps them, near the root.
+func TestSplayLocality(t *testing.T) {
+	var tr SplayTree
+	for i := 0; i < 1000; i++ {
+		tr.Insert(i, i)
+	}
+	if h := tr.Height(); h != 1000 {
+		t.Fatalf("expected sequential inserts to make a chain, got height %d", h)
+	}
+
+	// The first access to the bottom of the chain roughly halves its depth.
+	tr.Get(0)
+	if h := tr.Height(); h > 600 {
+		t.Fatalf("expected splaying to shorten the chain, got height %d", h)
+	}
+
+	hot := []int{10, 500, 990}
+	for i := 0; i < 30; i++ {
+		tr.Get(hot[i%len(hot)])
+	}
+	depth := make(map[int]int)
+	tr.LevelOrderTraverse(func(key int, value interface{}, d int) bool {
+		depth[key] = d
+		return true
+	})
+	for _, k := range hot {
+		if depth[k] > 2 {
+			t.Fatalf("expected %d near the root, got depth %d", k, depth[k])
+		}
+	}
+	if err := tr.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSplayRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var tr SplayTree
+	model := make(map[int]int)
+
+	for i := 0; i < 5000; i++ {
+		key := r.Intn(300)
+		switch r.Intn(3) {
+		case 0:
+			tr.Insert(key, i)
+			model[key] = i
+		case 1:
+			v, ok := tr.Delete(key)
+			mv, mok := model[key]
+			if ok != mok || (ok && v != mv) {
+				t.Fatalf("delete %d: expected %v %v, got %v %v", key, mv, mok, v, ok)
+			}
+			delete(model, key)
+		default:
+			v, ok := tr.Get(key)
+			mv, mok := model[key]
+			if ok != mok || (ok && v != mv) {
+				t.Fatalf("get %d: expected %v %v, got %v %v", key, mv, mok, v, ok)
+			}
+		}
+
+		if err := tr.Validate(); err != nil {
+			t.Fatalf("after %d operations: %v", i+1, err)
+		}
+	}
+
+	var keys []int
+	for k := range model {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	for i, k := range keys {
+		if key, _, _ := tr.Select(i); key != k {
+			t.Fatalf("select %d: expected %d, got %d", i, k, key)
+		}
+	}
+}
+
+func BenchmarkAccessPattern(b *testing.B) {
+	const n = 1 << 14
+	keys := rand.New(rand.NewSource(1)).Perm(n)
+
+	// Each trace is a sequence of keys to look up.
+	traces := []struct {
+		name  string
+		trace func(r *rand.Rand, count int) []int
+	}{
+		{"Uniform", func(r *rand.Rand, count int) []int {
+			trace := make([]int, count)
+			for i := range trace {
+				trace[i] = r.Intn(n)
+			}
+			return trace
+		}},
+		{"Zipfian", func(r *rand.Rand, count int) []int {
+			// Rank the keys in a random order, so the hot keys
+			// aren't all in one corner of the tree.
+			z := rand.NewZipf(r, 1.1, 1, n-1)
+			trace := make([]int, count)
+			for i := range trace {
+				trace[i] = keys[z.Uint64()]
+			}
+			return trace
+		}},
+		{"Sequential", func(r *rand.Rand, count int) []int {
+			trace := make([]int, count)
+			for i := range trace {
+				trace[i] = i % n
+			}
+			return trace
+		}},
+	}
+
+	for _, tc := range traces {
+		trace := tc.trace(rand.New(rand.NewSource(2)), 1<<16)
+
+		for _, m := range orderedMaps {
+			tr := m.new()
+			for _, k := range keys {
+				tr.Insert(k, k)
+			}
+
+			b.Run(tc.name+"/"+m.name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					tr.Get(trace[i%len(trace)])
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package binarysearchtree
+
+import "errors"
+
+var errOverlap = errors.New("Cannot join trees whose keys overlap")
+
+// joinFn returns a tree of the keys of l, then k, then the keys of r,
+// which must all be in ascending order. Each kind of tree has its own
+// so that Split, Join and Merge can keep the tree balanced. Split and
+// Merge are built from it by cutting and gluing trees around a node.
+type joinFn func(l, k, r *Node) *Node
+
+// Split divides the tree in two, returning a tree of the keys less
+// than key and a tree of the rest, in O(h). The nodes are reused, so
+// n shouldn't be used afterwards.
+func (n *Node) Split(key int) (left, right *Node) {
+	return splitWith(n, key, nodeJoin)
+}
+
+// Join returns a tree of the keys of n followed by the keys of right,
+// in O(h). It returns an error, and leaves both trees as they were,
+// unless every key in n is less than every key in right. Otherwise the
+// nodes are reused, so neither tree should be used afterwards.
+func (n *Node) Join(right *Node) (*Node, error) {
+	return concat(n, right, nodeJoin)
+}
+
+// Merge returns a tree of minimum height holding the keys of n and
+// other, in O(n+m). Where both hold a key the value from other is
+// kept. The nodes are reused, so neither tree should be used
+// afterwards.
+func (n *Node) Merge(other *Node) *Node {
+	return mergeWith(n, other, nodeJoin)
+}
+
+// Split moves the keys less than key into left and the rest
+// into right, in O(h), leaving t empty.
+func (t *Tree) Split(key int) (left, right *Tree) {
+	l, r := t.split(key, nodeJoin)
+	return &Tree{l}, &Tree{r}
+}
+
+// Join moves the keys of right, which must all be greater than the
+// keys of t, into t in O(h), leaving right empty.
+func (t *Tree) Join(right *Tree) error {
+	return t.join(&right.tree, nodeJoin)
+}
+
+// Merge moves the keys of other into t in O(n+m), leaving other
+// empty and t of minimum height. Where both hold a key the value
+// from other is kept.
+func (t *Tree) Merge(other *Tree) {
+	t.merge(&other.tree, nodeJoin)
+}
+
+// Split moves the keys less than key into left and the rest into
+// right, in O(log n), leaving t empty. Both halves stay balanced.
+func (t *AVLTree) Split(key int) (left, right *AVLTree) {
+	l, r := t.split(key, avlJoin)
+	return &AVLTree{l}, &AVLTree{r}
+}
+
+// Join moves the keys of right, which must all be greater than the
+// keys of t, into t in O(log n), leaving right empty.
+func (t *AVLTree) Join(right *AVLTree) error {
+	return t.join(&right.tree, avlJoin)
+}
+
+// Merge moves the keys of other into t in O(n+m), leaving other
+// empty. Where both hold a key the value from other is kept.
+func (t *AVLTree) Merge(other *AVLTree) {
+	t.merge(&other.tree, avlJoin)
+}
+
+// Split moves the keys less than key into left and the rest into
+// right, in O(log² n), leaving t empty. Both halves stay balanced.
+func (t *RBTree) Split(key int) (left, right *RBTree) {
+	l, r := t.split(key, rbJoin)
+	// A half which was split off whole may have a red root.
+	for _, root := range []*Node{l.root, r.root} {
+		if root != nil {
+			root.red = false
+		}
+	}
+	return &RBTree{l}, &RBTree{r}
+}
+
+// Join moves the keys of right, which must all be greater than the
+// keys of t, into t in O(log n), leaving right empty.
+func (t *RBTree) Join(right *RBTree) error {
+	return t.join(&right.tree, rbJoin)
+}
+
+// Merge moves the keys of other into t in O(n+m), leaving other
+// empty. Where both hold a key the value from other is kept.
+func (t *RBTree) Merge(other *RBTree) {
+	t.merge(&other.tree, rbJoin)
+}
+
+func (t *tree) split(key int, join joinFn) (left, right tree) {
+	l, r := splitWith(t.root, key, join)
+	t.Clear()
+	return tree{l, size(l)}, tree{r, size(r)}
+}
+
+func (t *tree) join(right *tree, join joinFn) error {
+	root, err := concat(t.root, right.root, join)
+	if err != nil {
+		return err
+	}
+	t.root = root
+	t.size += right.size
+	right.Clear()
+	return nil
+}
+
+func (t *tree) merge(other *tree, join joinFn) {
+	t.root = mergeWith(t.root, other.root, join)
+	t.size = size(t.root)
+	other.Clear()
+}
+
+// splitWith returns trees of the keys less than key and of the rest.
+func splitWith(n *Node, key int, join joinFn) (left, right *Node) {
+	left, eq, right := splitAt(n, key, join)
+	if eq != nil {
+		right = join(nil, eq, right)
+	}
+	return left, right
+}
+
+// splitAt returns trees of the keys less than key and greater than
+// key, and the node holding key if there is one, detached from both.
+// The recursion follows the search path, joining the subtrees hanging
+// off it back together on either side.
+func splitAt(n *Node, key int, join joinFn) (lt, eq, gt *Node) {
+	if n == nil {
+		return nil, nil, nil
+	}
+
+	left, right := n.Left, n.Right
+	switch {
+	case key < n.Key:
+		lt, eq, gt = splitAt(left, key, join)
+		return lt, eq, join(gt, n, right)
+	case key > n.Key:
+		lt, eq, gt = splitAt(right, key, join)
+		return join(left, n, lt), eq, gt
+	}
+	return left, n, right
+}
+
+// concat joins two trees using the min of right as the middle node.
+func concat(left, right *Node, join joinFn) (*Node, error) {
+	if left == nil {
+		return right, nil
+	}
+	if right == nil {
+		return left, nil
+	}
+
+	min := right.Min()
+	if left.Max().Key >= min.Key {
+		return nil, errOverlap
+	}
+	_, k, rest := splitAt(right, min.Key, join)
+	return join(left, k, rest), nil
+}
+
+// mergeWith merges the nodes of a and b in order and builds them into
+// a new tree, so it takes O(n+m) however the keys interleave.
+func mergeWith(a, b *Node, join joinFn) *Node {
+	as, bs := inOrderNodes(a), inOrderNodes(b)
+	merged := make([]*Node, 0, len(as)+len(bs))
+	for len(as) > 0 && len(bs) > 0 {
+		switch {
+		case as[0].Key < bs[0].Key:
+			merged = append(merged, as[0])
+			as = as[1:]
+		case as[0].Key > bs[0].Key:
+			merged = append(merged, bs[0])
+			bs = bs[1:]
+		default:
+			merged = append(merged, bs[0])
+			as, bs = as[1:], bs[1:]
+		}
+	}
+	merged = append(merged, as...)
+	merged = append(merged, bs...)
+	return buildWith(merged, join)
+}
+
+// buildWith builds a tree from the sorted nodes by joining the trees
+// built from either half around the middle node. Each join is of
+// trees of nearly the same height, so it takes O(n) in all.
+func buildWith(nodes []*Node, join joinFn) *Node {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	mid := len(nodes) / 2
+	l := buildWith(nodes[:mid], join)
+	r := buildWith(nodes[mid+1:], join)
+	return join(l, nodes[mid], r)
+}
+
+// nodeJoin makes k the root of l and r. It keeps the height of the
+// tree within one of the taller of them, but doesn't balance it.
+func nodeJoin(l, k, r *Node) *Node {
+	k.Left, k.Right = l, r
+	updateSize(k)
+	return k
+}
+
+// avlJoin hangs k, with l and r as its children, from the spine of
+// the taller tree at the point where their heights are within one,
+// and rebalances on the way back up, so it takes O(|h(l) - h(r)|).
+func avlJoin(l, k, r *Node) *Node {
+	lh, rh := nodeHeight(l), nodeHeight(r)
+	switch {
+	case lh > rh+1:
+		l.Right = avlJoin(l.Right, k, r)
+		return rebalance(l)
+	case rh > lh+1:
+		r.Left = avlJoin(l, k, r.Left)
+		return rebalance(r)
+	}
+
+	k.Left, k.Right = l, r
+	updateHeight(k)
+	updateSize(k)
+	return k
+}
+
+// rbJoin hangs k, coloured red with l and r as its children, from the
+// spine of the tree with the greater black height at a black node of
+// the same black height as the other tree. As when inserting a red
+// leaf, rbBalance then restores the invariants on the way back up.
+func rbJoin(l, k, r *Node) *Node {
+	// The subtrees of a valid tree are valid once their roots are black.
+	if l != nil {
+		l.red = false
+	}
+	if r != nil {
+		r.red = false
+	}
+
+	var root *Node
+	switch lh, rh := blackHeight(l), blackHeight(r); {
+	case lh > rh:
+		root = rbJoinRight(l, k, r, lh, rh)
+	case lh < rh:
+		root = rbJoinLeft(l, k, r, lh, rh)
+	default:
+		k.Left, k.Right = l, r
+		updateSize(k)
+		root = k
+	}
+	root.red = false
+	return root
+}
+
+// rbJoinRight descends the right spine of l, which has black height lh.
+func rbJoinRight(l, k, r *Node, lh, rh int) *Node {
+	if lh == rh && !isRed(l) {
+		k.Left, k.Right, k.red = l, r, true
+		updateSize(k)
+		return k
+	}
+	if !isRed(l) {
+		lh--
+	}
+	l.Right = rbJoinRight(l.Right, k, r, lh, rh)
+	return rbBalance(l)
+}
+
+// rbJoinLeft descends the left spine of r, which has black height rh.
+func rbJoinLeft(l, k, r *Node, lh, rh int) *Node {
+	if lh == rh && !isRed(r) {
+		k.Left, k.Right, k.red = l, r, true
+		updateSize(k)
+		return k
+	}
+	if !isRed(r) {
+		rh--
+	}
+	r.Left = rbJoinLeft(l, k, r.Left, lh, rh)
+	return rbBalance(r)
+}
+
+// blackHeight returns the number of black nodes on the path from n
+// to a leaf, which is the same for every path.
+func blackHeight(n *Node) int {
+	h := 0
+	for ; n != nil; n = n.Left {
+		if !n.red {
+			h++
+		}
+	}
+	return h
+}
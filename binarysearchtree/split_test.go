@@ -0,0 +1,257 @@
+package binarysearchtree
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+type validator interface {
+	Validate() error
+}
+
+var splitTrees = []struct {
+	name  string
+	new   func() OrderedMap
+	split func(m OrderedMap, key int) (OrderedMap, OrderedMap)
+	join  func(l, r OrderedMap) error
+	merge func(a, b OrderedMap)
+}{
+	{
+		"Node",
+		func() OrderedMap { return &Tree{} },
+		func(m OrderedMap, key int) (OrderedMap, OrderedMap) { return m.(*Tree).Split(key) },
+		func(l, r OrderedMap) error { return l.(*Tree).Join(r.(*Tree)) },
+		func(a, b OrderedMap) { a.(*Tree).Merge(b.(*Tree)) },
+	},
+	{
+		"AVL",
+		func() OrderedMap { return &AVLTree{} },
+		func(m OrderedMap, key int) (OrderedMap, OrderedMap) { return m.(*AVLTree).Split(key) },
+		func(l, r OrderedMap) error { return l.(*AVLTree).Join(r.(*AVLTree)) },
+		func(a, b OrderedMap) { a.(*AVLTree).Merge(b.(*AVLTree)) },
+	},
+	{
+		"RB",
+		func() OrderedMap { return &RBTree{} },
+		func(m OrderedMap, key int) (OrderedMap, OrderedMap) { return m.(*RBTree).Split(key) },
+		func(l, r OrderedMap) error { return l.(*RBTree).Join(r.(*RBTree)) },
+		func(a, b OrderedMap) { a.(*RBTree).Merge(b.(*RBTree)) },
+	},
+}
+
+// keysOf returns the keys of the map in order.
+func keysOf(m OrderedMap) []int {
+	var keys []int
+	m.InOrderTraverse(func(key int, value interface{}) {
+		keys = append(keys, key)
+	})
+	return keys
+}
+
+func validate(t *testing.T, m OrderedMap) {
+	t.Helper()
+	if err := m.(validator).Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	for _, st := range splitTrees {
+		t.Run(st.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			for _, n := range []int{0, 1, 2, 10, 100, 1000} {
+				for _, key := range []int{-1, 0, n / 3, n, 2 * n, 2*n + 1, 4 * n} {
+					m := st.new()
+					for _, k := range r.Perm(n) {
+						m.Insert(2*k, k)
+					}
+					keys := keysOf(m)
+
+					left, right := st.split(m, key)
+					validate(t, left)
+					validate(t, right)
+					if m.Len() != 0 {
+						t.Fatalf("expected the split tree to be empty, got %d keys", m.Len())
+					}
+
+					lk, rk := keysOf(left), keysOf(right)
+					if len(lk) != left.Len() || len(rk) != right.Len() {
+						t.Fatalf("expected %d and %d keys, got %d and %d", len(lk), len(rk), left.Len(), right.Len())
+					}
+					if fmt.Sprint(append(lk, rk...)) != fmt.Sprint(keys) {
+						t.Fatalf("%d/%d: expected %v, got %v and %v", n, key, keys, lk, rk)
+					}
+					if (len(lk) > 0 && lk[len(lk)-1] >= key) || (len(rk) > 0 && rk[0] < key) {
+						t.Fatalf("%d/%d: keys on the wrong side, got %v and %v", n, key, lk, rk)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestJoin(t *testing.T) {
+	for _, st := range splitTrees {
+		t.Run(st.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			// Uneven sizes make the join descend deep into the taller tree.
+			for _, sizes := range [][2]int{{0, 0}, {0, 5}, {5, 0}, {1, 1000}, {1000, 1}, {10, 300}, {300, 10}, {500, 500}} {
+				left, right := st.new(), st.new()
+				for _, k := range r.Perm(sizes[0]) {
+					left.Insert(k, k)
+				}
+				for _, k := range r.Perm(sizes[1]) {
+					right.Insert(sizes[0]+k, k)
+				}
+
+				if err := st.join(left, right); err != nil {
+					t.Fatal(err)
+				}
+				validate(t, left)
+				if right.Len() != 0 {
+					t.Fatalf("expected the joined tree to be empty, got %d keys", right.Len())
+				}
+
+				keys := keysOf(left)
+				if len(keys) != sizes[0]+sizes[1] || left.Len() != len(keys) {
+					t.Fatalf("%v: expected %d keys, got %d", sizes, sizes[0]+sizes[1], len(keys))
+				}
+				for i, k := range keys {
+					if k != i {
+						t.Fatalf("%v: expected key %d, got %d", sizes, i, k)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestJoinOverlap(t *testing.T) {
+	for _, st := range splitTrees {
+		t.Run(st.name, func(t *testing.T) {
+			for name, keys := range map[string][2][]int{
+				"Overlapping": {{1, 5, 9}, {4, 12}},
+				"Touching":    {{1, 5, 9}, {9, 12}},
+				"Reversed":    {{10, 12}, {1, 5}},
+			} {
+				left, right := st.new(), st.new()
+				for _, k := range keys[0] {
+					left.Insert(k, k)
+				}
+				for _, k := range keys[1] {
+					right.Insert(k, k)
+				}
+
+				if err := st.join(left, right); err == nil {
+					t.Fatalf("%s: expected an error", name)
+				}
+				if fmt.Sprint(keysOf(left)) != fmt.Sprint(keys[0]) || fmt.Sprint(keysOf(right)) != fmt.Sprint(keys[1]) {
+					t.Fatalf("%s: expected the trees to be unchanged", name)
+				}
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	for _, st := range splitTrees {
+		t.Run(st.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			for _, sizes := range [][2]int{{0, 0}, {0, 50}, {50, 0}, {1, 500}, {300, 300}} {
+				a, b := st.new(), st.new()
+				model := make(map[int]string)
+				for i := 0; i < sizes[0]; i++ {
+					k := r.Intn(1000)
+					a.Insert(k, "a")
+					model[k] = "a"
+				}
+				for i := 0; i < sizes[1]; i++ {
+					k := r.Intn(1000)
+					b.Insert(k, "b")
+					model[k] = "b"
+				}
+
+				st.merge(a, b)
+				validate(t, a)
+				if b.Len() != 0 {
+					t.Fatalf("expected the merged tree to be empty, got %d keys", b.Len())
+				}
+				if a.Len() != len(model) {
+					t.Fatalf("%v: expected %d keys, got %d", sizes, len(model), a.Len())
+				}
+				a.InOrderTraverse(func(key int, value interface{}) {
+					if value != model[key] {
+						t.Fatalf("%v: %d: expected %s, got %v", sizes, key, model[key], value)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestNodeSplitJoinMerge(t *testing.T) {
+	left, right := gapTree().Split(65)
+	if err := left.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := right.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if result := inOrder(left); !isSameSlice(result, []string{"10", "20", "30", "40", "50", "60"}) {
+		t.Fatalf("unexpected left keys %v", result)
+	}
+	if result := inOrder(right); !isSameSlice(result, []string{"70", "80", "90", "100", "110"}) {
+		t.Fatalf("unexpected right keys %v", result)
+	}
+
+	if _, err := right.Join(left); err == nil {
+		t.Fatal("expected an error")
+	}
+	joined, err := left.Join(right)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := joined.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateSizes(joined); err != nil {
+		t.Fatal(err)
+	}
+	if !joined.SameContents(gapTree()) {
+		t.Fatalf("expected %v, got %v", inOrder(gapTree()), inOrder(joined))
+	}
+
+	merged := chain(100, false).Merge(chain(50, true))
+	if err := merged.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateSizes(merged); err != nil {
+		t.Fatal(err)
+	}
+	if merged.Size() != 100 || merged.Height() != 7 {
+		t.Fatalf("expected 100 keys in 7 levels, got %d in %d", merged.Size(), merged.Height())
+	}
+}
+
+func BenchmarkSplitJoin(b *testing.B) {
+	const keys = 1 << 16
+
+	for _, st := range splitTrees {
+		b.Run(st.name, func(b *testing.B) {
+			m := st.new()
+			for _, k := range rand.New(rand.NewSource(1)).Perm(keys) {
+				m.Insert(k, k)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				left, right := st.split(m, i%keys)
+				if err := st.join(left, right); err != nil {
+					b.Fatal(err)
+				}
+				m = left
+			}
+		})
+	}
+}
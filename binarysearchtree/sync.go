@@ -0,0 +1,157 @@
+package binarysearchtree
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	_ OrderedMap = &SyncTree{}
+	_ OrderedMap = &LockedTree{}
+)
+
+// SyncTree is an ordered map which is safe for concurrent use. Each
+// write publishes a new version of a PersistentTree, so readers never
+// block: they load the current version atomically and work on it while
+// writers carry on. Writers are serialised by a mutex, but as a write
+// only copies O(log n) nodes they hold it briefly.
+//
+// Every read method sees a single consistent version of the tree, even
+// a traversal which runs concurrently with writes. The zero value is
+// an empty tree ready to use.
+type SyncTree struct {
+	mu      sync.Mutex
+	current atomic.Pointer[PersistentTree]
+}
+
+// Snapshot returns the current version of the tree. It will never
+// change, however the SyncTree is modified later.
+func (t *SyncTree) Snapshot() PersistentTree {
+	if p := t.current.Load(); p != nil {
+		return *p
+	}
+	return PersistentTree{}
+}
+
+// Update replaces the tree with the result of fn, which is called
+// with the current version while other writers are held off. It
+// allows several changes to be made atomically.
+func (t *SyncTree) Update(fn func(PersistentTree) PersistentTree) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	next := fn(t.Snapshot())
+	t.current.Store(&next)
+}
+
+// Insert adds the given key and value to the tree, replacing
+// the value if the key already exists.
+func (t *SyncTree) Insert(key int, value interface{}) {
+	t.Update(func(pt PersistentTree) PersistentTree {
+		return pt.Insert(key, value)
+	})
+}
+
+// Delete removes the key from the tree and returns
+// the value it was associated with.
+func (t *SyncTree) Delete(key int) (interface{}, bool) {
+	var value interface{}
+	var ok bool
+	t.Update(func(pt PersistentTree) PersistentTree {
+		if value, ok = pt.Get(key); !ok {
+			return pt
+		}
+		pt, _ = pt.Remove(key)
+		return pt
+	})
+	return value, ok
+}
+
+// Get returns the value associated with the key.
+func (t *SyncTree) Get(key int) (interface{}, bool) {
+	return t.Snapshot().Get(key)
+}
+
+// Len returns the number of keys in the tree.
+func (t *SyncTree) Len() int {
+	return t.Snapshot().Len()
+}
+
+// Min returns the min key in the tree and its value.
+func (t *SyncTree) Min() (int, interface{}, bool) {
+	return t.Snapshot().Min()
+}
+
+// Max returns the max key in the tree and its value.
+func (t *SyncTree) Max() (int, interface{}, bool) {
+	return t.Snapshot().Max()
+}
+
+// InOrderTraverse calls Visitor for each key in ascending order,
+// over the version of the tree current when it was called. Visitor
+// may safely modify the tree.
+func (t *SyncTree) InOrderTraverse(v Visitor) {
+	t.Snapshot().InOrderTraverse(v)
+}
+
+// LockedTree is an AVLTree guarded by a sync.RWMutex. Readers run in
+// parallel with each other but not with writers. Its writes are much
+// cheaper than SyncTree's, as they don't copy the path to the key, but
+// a long traversal holds up every writer until it finishes.
+type LockedTree struct {
+	mu   sync.RWMutex
+	tree AVLTree
+}
+
+// Insert adds the given key and value to the tree, replacing
+// the value if the key already exists.
+func (t *LockedTree) Insert(key int, value interface{}) {
+	t.mu.Lock()
+	t.tree.Insert(key, value)
+	t.mu.Unlock()
+}
+
+// Delete removes the key from the tree and returns
+// the value it was associated with.
+func (t *LockedTree) Delete(key int) (interface{}, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.tree.Delete(key)
+}
+
+// Get returns the value associated with the key.
+func (t *LockedTree) Get(key int) (interface{}, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tree.Get(key)
+}
+
+// Len returns the number of keys in the tree.
+func (t *LockedTree) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tree.Len()
+}
+
+// Min returns the min key in the tree and its value.
+func (t *LockedTree) Min() (int, interface{}, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tree.Min()
+}
+
+// Max returns the max key in the tree and its value.
+func (t *LockedTree) Max() (int, interface{}, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tree.Max()
+}
+
+// InOrderTraverse calls Visitor for each key in ascending order.
+// The read lock is held throughout, so the traversal sees a consistent
+// tree, but Visitor must not modify the tree or it will deadlock.
+func (t *LockedTree) InOrderTraverse(v Visitor) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	t.tree.InOrderTraverse(v)
+}
@@ -0,0 +1,197 @@
+package binarysearchtree
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+var concurrentMaps = []struct {
+	name string
+	new  func() OrderedMap
+}{
+	{"Sync", func() OrderedMap { return &SyncTree{} }},
+	{"Locked", func() OrderedMap { return &LockedTree{} }},
+}
+
+func TestConcurrentBasic(t *testing.T) {
+	for _, m := range concurrentMaps {
+		t.Run(m.name, func(t *testing.T) {
+			tr := m.new()
+			if _, ok := tr.Get(1); ok {
+				t.Fatal("expected no value")
+			}
+			if _, _, ok := tr.Min(); ok {
+				t.Fatal("expected no min")
+			}
+
+			for _, k := range []int{5, 3, 8, 1} {
+				tr.Insert(k, fmt.Sprint(k))
+			}
+			if v, ok := tr.Delete(3); !ok || v != "3" {
+				t.Fatalf("expected 3, got %v", v)
+			}
+			if _, ok := tr.Delete(3); ok {
+				t.Fatal("expected nothing to delete")
+			}
+			if result := inOrder(tr); !isSameSlice(result, []string{"1", "5", "8"}) {
+				t.Fatalf("unexpected keys %v", result)
+			}
+			if k, _, _ := tr.Max(); k != 8 || tr.Len() != 3 {
+				t.Fatalf("expected max 8 and 3 keys, got %d and %d", k, tr.Len())
+			}
+		})
+	}
+}
+
+// TestConcurrentStress runs readers and writers together and is
+// intended to be run with the race detector.
+func TestConcurrentStress(t *testing.T) {
+	const (
+		writers = 4
+		readers = 4
+		ops     = 2000
+	)
+
+	for _, m := range concurrentMaps {
+		t.Run(m.name, func(t *testing.T) {
+			tr := m.new()
+			var wg sync.WaitGroup
+
+			for w := 0; w < writers; w++ {
+				wg.Add(1)
+				go func(w int) {
+					defer wg.Done()
+					r := rand.New(rand.NewSource(int64(w)))
+					for i := 0; i < ops; i++ {
+						// Each writer owns the keys equal to w mod writers.
+						key := r.Intn(256)*writers + w
+						if r.Intn(3) == 0 {
+							tr.Delete(key)
+						} else {
+							tr.Insert(key, key)
+						}
+					}
+				}(w)
+			}
+
+			for g := 0; g < readers; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					r := rand.New(rand.NewSource(int64(g + writers)))
+					for i := 0; i < ops; i++ {
+						if v, ok := tr.Get(r.Intn(256 * writers)); ok && v == nil {
+							t.Error("found a key without its value")
+							return
+						}
+
+						if i%100 == 0 {
+							last := -1
+							tr.InOrderTraverse(func(key int, value interface{}) {
+								if key <= last || value != key {
+									t.Errorf("inconsistent traversal at %d", key)
+								}
+								last = key
+							})
+						}
+					}
+				}(g)
+			}
+			wg.Wait()
+
+			count := 0
+			tr.InOrderTraverse(func(key int, value interface{}) {
+				count++
+			})
+			if count != tr.Len() {
+				t.Fatalf("expected %d keys, traversed %d", tr.Len(), count)
+			}
+		})
+	}
+}
+
+// TestSyncSnapshotConsistency checks that a traversal running alongside
+// writers sees whole updates: keys are only ever added and removed in
+// pairs, so every traversal must find both halves of each pair.
+func TestSyncSnapshotConsistency(t *testing.T) {
+	var tr SyncTree
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < 5000; i++ {
+			key := r.Intn(500) * 2
+			tr.Update(func(pt PersistentTree) PersistentTree {
+				if _, ok := pt.Get(key); ok {
+					pt, _ = pt.Remove(key)
+					pt, _ = pt.Remove(key + 1)
+					return pt
+				}
+				return pt.Insert(key, key).Insert(key+1, key+1)
+			})
+		}
+		close(done)
+	}()
+
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				snapshot := tr.Snapshot()
+				if snapshot.Len()%2 != 0 {
+					t.Errorf("snapshot has %d keys", snapshot.Len())
+					return
+				}
+				snapshot.InOrderTraverse(func(key int, value interface{}) {
+					if _, ok := snapshot.Get(key ^ 1); !ok {
+						t.Errorf("key %d is missing its pair", key)
+					}
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := tr.Snapshot().Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkConcurrent(b *testing.B) {
+	const keys = 1 << 14
+
+	for _, writes := range []int{0, 10, 50} {
+		for _, m := range concurrentMaps {
+			b.Run(fmt.Sprintf("%dPercentWrites/%s", writes, m.name), func(b *testing.B) {
+				tr := m.new()
+				for i := 0; i < keys; i++ {
+					tr.Insert(i, i)
+				}
+
+				b.RunParallel(func(pb *testing.PB) {
+					r := rand.New(rand.NewSource(rand.Int63()))
+					for pb.Next() {
+						key := r.Intn(keys)
+						if r.Intn(100) < writes {
+							tr.Insert(key, key)
+						} else {
+							tr.Get(key)
+						}
+					}
+				})
+			})
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package binarysearchtree
+
+import "fmt"
+
+var (
+	_ OrderedMap = &Tree{}
+	_ OrderedMap = &AVLTree{}
+	_ OrderedMap = &RBTree{}
+	_ OrderedMap = &SplayTree{}
+)
+
+// OrderedMap is an interface for a map which visits its keys in order.
+type OrderedMap interface {
+	Insert(key int, value interface{})
+	Get(key int) (interface{}, bool)
+	Delete(key int) (interface{}, bool)
+	Min() (int, interface{}, bool)
+	Max() (int, interface{}, bool)
+	Len() int
+	InOrderTraverse(v Visitor)
+}
+
+// tree holds the root of a binary search tree and implements
+// the read only operations shared by the trees in this package.
+type tree struct {
+	root *Node
+	size int
+}
+
+// Get returns the value associated with the key.
+func (t *tree) Get(key int) (interface{}, bool) {
+	n := t.root.Exact(key)
+	if n == nil {
+		return nil, false
+	}
+	return n.Value, true
+}
+
+// Search returns true if the given key is found within the tree.
+func (t *tree) Search(key int) bool {
+	return t.root.Search(key)
+}
+
+// Len returns the number of keys in the tree.
+func (t *tree) Len() int {
+	return t.size
+}
+
+// Clear removes every key from the tree.
+func (t *tree) Clear() {
+	t.root = nil
+	t.size = 0
+}
+
+// Height returns the number of nodes on the longest path from the
+// root to a leaf, zero for an empty tree.
+func (t *tree) Height() int {
+	return t.root.Height()
+}
+
+// Validate returns an error if a key is out of order or a stored
+// count of nodes is wrong.
+func (t *tree) Validate() error {
+	if err := t.root.Validate(); err != nil {
+		return err
+	}
+	if err := validateSizes(t.root); err != nil {
+		return err
+	}
+	if count := size(t.root); count != t.size {
+		return fmt.Errorf("expected %d nodes, counted %d", t.size, count)
+	}
+	return nil
+}
+
+// BalanceFactor returns the height of the root's left subtree
+// less the height of its right subtree.
+func (t *tree) BalanceFactor() int {
+	return t.root.BalanceFactor()
+}
+
+// IsBalanced returns true if the subtrees of every node
+// differ in height by at most one.
+func (t *tree) IsBalanced() bool {
+	return t.root.IsBalanced()
+}
+
+// DepthDistribution returns the number of nodes at each depth of the tree.
+func (t *tree) DepthDistribution() []int {
+	return t.root.DepthDistribution()
+}
+
+// Min returns the min key in the tree and its value.
+func (t *tree) Min() (int, interface{}, bool) {
+	return entry(t.root.Min())
+}
+
+// Max returns the max key in the tree and its value.
+func (t *tree) Max() (int, interface{}, bool) {
+	return entry(t.root.Max())
+}
+
+// Nearest returns the key at which the search for the specified key
+// ends, which is its floor or ceiling if it isn't in the tree, and its value.
+func (t *tree) Nearest(key int) (int, interface{}, bool) {
+	return entry(t.root.Nearest(key))
+}
+
+// Floor returns the largest key less than or equal to the specified key and its value.
+func (t *tree) Floor(key int) (int, interface{}, bool) {
+	return entry(t.root.Floor(key))
+}
+
+// Ceiling returns the smallest key greater than or equal to the specified key and its value.
+func (t *tree) Ceiling(key int) (int, interface{}, bool) {
+	return entry(t.root.Ceiling(key))
+}
+
+// Predecessor returns the largest key strictly less than the specified key and its value.
+func (t *tree) Predecessor(key int) (int, interface{}, bool) {
+	return entry(t.root.Predecessor(key))
+}
+
+// Successor returns the smallest key strictly greater than the specified key and its value.
+func (t *tree) Successor(key int) (int, interface{}, bool) {
+	return entry(t.root.Successor(key))
+}
+
+// Range calls Visitor in ascending order for each key between lo and hi inclusive.
+func (t *tree) Range(lo, hi int, v Visitor) {
+	t.root.Range(lo, hi, v)
+}
+
+// Rank returns the number of keys in the tree less than the specified key.
+func (t *tree) Rank(key int) int {
+	return t.root.Rank(key)
+}
+
+// Select returns the kth smallest key, counting from zero, and its value.
+func (t *tree) Select(k int) (int, interface{}, bool) {
+	return entry(t.root.Select(k))
+}
+
+// CountRange returns the number of keys between lo and hi inclusive.
+func (t *tree) CountRange(lo, hi int) int {
+	return t.root.CountRange(lo, hi)
+}
+
+// Median returns the median key, the lower of the two middle
+// keys when the tree has an even size, and its value.
+func (t *tree) Median() (int, interface{}, bool) {
+	return entry(t.root.Median())
+}
+
+// InOrderTraverse calls Visitor for each key in ascending order.
+func (t *tree) InOrderTraverse(v Visitor) {
+	t.root.InOrderTraverse(v)
+}
+
+// PreOrderTraverse calls Visitor for each node before its children.
+func (t *tree) PreOrderTraverse(v Visitor) {
+	t.root.PreOrderTraverse(v)
+}
+
+// PostOrderTraverse calls Visitor for each node after its children.
+func (t *tree) PostOrderTraverse(v Visitor) {
+	t.root.PostOrderTraverse(v)
+}
+
+// InOrderWalk calls fn for each key in ascending order until it returns false.
+func (t *tree) InOrderWalk(fn WalkFn) {
+	t.root.InOrderWalk(fn)
+}
+
+// PreOrderWalk calls fn for each node before its children until it returns false.
+func (t *tree) PreOrderWalk(fn WalkFn) {
+	t.root.PreOrderWalk(fn)
+}
+
+// PostOrderWalk calls fn for each node after its children until it returns false.
+func (t *tree) PostOrderWalk(fn WalkFn) {
+	t.root.PostOrderWalk(fn)
+}
+
+// LevelOrderTraverse calls LevelVisitor for each node a level at a time.
+func (t *tree) LevelOrderTraverse(v LevelVisitor) {
+	t.root.LevelOrderTraverse(v)
+}
+
+// Iterator returns an unpositioned iterator over the tree.
+func (t *tree) Iterator() *Iterator {
+	return t.root.Iterator()
+}
+
+// Tree owns the root of a binary search tree, so unlike Node
+// it can be empty and its root can be removed.
+type Tree struct {
+	tree
+}
+
+// Insert adds the given key and value to the tree, replacing
+// the value if the key already exists.
+func (t *Tree) Insert(key int, value interface{}) {
+	if t.root == nil {
+		t.root = &Node{Key: key, Value: value}
+		t.size++
+		return
+	}
+
+	if insert(t.root, key, value) {
+		t.size++
+	}
+}
+
+// Delete removes the key from the tree and returns
+// the value it was associated with.
+func (t *Tree) Delete(key int) (interface{}, bool) {
+	n := t.root.Exact(key)
+	if n == nil {
+		return nil, false
+	}
+
+	// remove may copy another node into n, so take the value first.
+	value := n.Value
+	t.root = remove(t.root, key)
+	t.size--
+	return value, true
+}
+
+func entry(n *Node) (int, interface{}, bool) {
+	if n == nil {
+		return 0, nil, false
+	}
+	return n.Key, n.Value, true
+}
@@ -0,0 +1,188 @@
+package binarysearchtree
+
+import (
+	"fmt"
+	"testing"
+)
+
+func fillKeys(t *Tree, keys ...int) {
+	for _, k := range keys {
+		t.Insert(k, fmt.Sprint(k))
+	}
+}
+
+func inOrder(t interface{ InOrderTraverse(Visitor) }) []string {
+	var result []string
+	t.InOrderTraverse(func(key int, value interface{}) {
+		result = append(result, fmt.Sprintf("%s", value))
+	})
+	return result
+}
+
+func TestTreeEmpty(t *testing.T) {
+	var tr Tree
+
+	if tr.Len() != 0 || tr.Height() != 0 {
+		t.Fatalf("expected empty tree, got len %d height %d", tr.Len(), tr.Height())
+	}
+	if _, ok := tr.Get(1); ok {
+		t.Fatal("expected no value")
+	}
+	if _, ok := tr.Delete(1); ok {
+		t.Fatal("expected no delete")
+	}
+	if _, _, ok := tr.Min(); ok {
+		t.Fatal("expected no min")
+	}
+	if _, _, ok := tr.Max(); ok {
+		t.Fatal("expected no max")
+	}
+	if _, _, ok := tr.Nearest(1); ok {
+		t.Fatal("expected no nearest")
+	}
+	if result := inOrder(&tr); len(result) != 0 {
+		t.Fatalf("expected no keys, got %v", result)
+	}
+}
+
+func TestTreeDelete(t *testing.T) {
+	tests := map[string]struct {
+		keys     []int
+		deletes  []int
+		expected []string
+		height   int
+	}{
+		"SingleNode": {
+			[]int{8},
+			[]int{8},
+			nil,
+			0,
+		},
+		"RootWithLeftChild": {
+			[]int{8, 4},
+			[]int{8},
+			[]string{"4"},
+			1,
+		},
+		"RootWithRightChild": {
+			[]int{8, 10},
+			[]int{8},
+			[]string{"10"},
+			1,
+		},
+		"RootWithTwoChildren": {
+			[]int{8, 4, 10, 2, 6, 1, 3, 5, 7, 9, 11},
+			[]int{8},
+			[]string{"1", "2", "3", "4", "5", "6", "7", "9", "10", "11"},
+			4,
+		},
+		"Leaf": {
+			[]int{8, 4, 10, 2, 6, 1, 3, 5, 7, 9, 11},
+			[]int{1, 3},
+			[]string{"2", "4", "5", "6", "7", "8", "9", "10", "11"},
+			4,
+		},
+		"Missing": {
+			[]int{8, 4, 10},
+			[]int{5},
+			[]string{"4", "8", "10"},
+			2,
+		},
+		"All": {
+			[]int{8, 4, 10, 2, 6},
+			[]int{4, 8, 2, 10, 6},
+			nil,
+			0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var tr Tree
+			fillKeys(&tr, test.keys...)
+
+			deleted := 0
+			for _, k := range test.deletes {
+				v, ok := tr.Delete(k)
+				if !ok {
+					continue
+				}
+				if v != fmt.Sprint(k) {
+					t.Fatalf("expected %d, got %v", k, v)
+				}
+				if _, ok := tr.Get(k); ok {
+					t.Fatalf("%d: still present", k)
+				}
+				deleted++
+			}
+
+			if tr.Len() != len(test.keys)-deleted {
+				t.Fatalf("expected len %d, got %d", len(test.keys)-deleted, tr.Len())
+			}
+			if result := inOrder(&tr); !isSameSlice(result, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, result)
+			}
+			if tr.Height() != test.height {
+				t.Fatalf("expected height %d, got %d", test.height, tr.Height())
+			}
+		})
+	}
+}
+
+func TestTreeInsert(t *testing.T) {
+	var tr Tree
+	fillKeys(&tr, 8, 4, 10)
+	tr.Insert(4, "four")
+
+	if tr.Len() != 3 {
+		t.Fatalf("expected len 3, got %d", tr.Len())
+	}
+	if v, _ := tr.Get(4); v != "four" {
+		t.Fatalf("expected four, got %v", v)
+	}
+	if !tr.Search(10) || tr.Search(11) {
+		t.Fatal("search not working")
+	}
+
+	if k, v, _ := tr.Min(); k != 4 || v != "four" {
+		t.Fatalf("expected min 4, got %d", k)
+	}
+	if k, _, _ := tr.Max(); k != 10 {
+		t.Fatalf("expected max 10, got %d", k)
+	}
+	if k, _, _ := tr.Nearest(9); k != 10 {
+		t.Fatalf("expected nearest 10, got %d", k)
+	}
+
+	tr.Clear()
+	if tr.Len() != 0 || tr.Height() != 0 {
+		t.Fatal("expected empty tree")
+	}
+	tr.Insert(1, "1")
+	if v, _ := tr.Get(1); v != "1" {
+		t.Fatalf("expected 1, got %v", v)
+	}
+}
+
+func TestTreeTraverse(t *testing.T) {
+	var tr Tree
+	fillKeys(&tr, 8, 4, 10, 2, 6, 1, 3, 5, 7, 9, 11)
+
+	var pre, post []string
+	tr.PreOrderTraverse(func(key int, value interface{}) {
+		pre = append(pre, fmt.Sprintf("%s", value))
+	})
+	tr.PostOrderTraverse(func(key int, value interface{}) {
+		post = append(post, fmt.Sprintf("%s", value))
+	})
+
+	if !isSameSlice(inOrder(&tr), []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}) {
+		t.Errorf("Traversal order incorrect, got %v", inOrder(&tr))
+	}
+	if !isSameSlice(pre, []string{"8", "4", "2", "1", "3", "6", "5", "7", "10", "9", "11"}) {
+		t.Errorf("Traversal order incorrect, got %v", pre)
+	}
+	if !isSameSlice(post, []string{"1", "3", "2", "5", "7", "6", "4", "9", "11", "10", "8"}) {
+		t.Errorf("Traversal order incorrect, got %v", post)
+	}
+}
@@ -0,0 +1,122 @@
+package binarysearchtree
+
+import "github.com/iainanderson83/datastructures/queue"
+
+// WalkFn is called for each key/value pair during a walk.
+// Returning false stops the walk.
+type WalkFn func(key int, value interface{}) bool
+
+// LevelVisitor is called for each node during a level order
+// traversal along with its depth, which is zero for the root.
+// Returning false stops the traversal.
+type LevelVisitor func(key int, value interface{}, depth int) bool
+
+// The walks below keep their own stack rather than recursing, so that
+// a degenerate tree millions of nodes deep can't exhaust the goroutine
+// stack.
+
+// InOrderWalk calls fn for each key in ascending order.
+func (n *Node) InOrderWalk(fn WalkFn) {
+	var stack []*Node
+	curr := n
+	for curr != nil || len(stack) > 0 {
+		for curr != nil {
+			stack = append(stack, curr)
+			curr = curr.Left
+		}
+
+		curr = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !fn(curr.Key, curr.Value) {
+			return
+		}
+		curr = curr.Right
+	}
+}
+
+// PreOrderWalk calls fn for each node before its children.
+func (n *Node) PreOrderWalk(fn WalkFn) {
+	if n == nil {
+		return
+	}
+
+	stack := []*Node{n}
+	for len(stack) > 0 {
+		curr := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !fn(curr.Key, curr.Value) {
+			return
+		}
+
+		// Push the right child first so that the left is visited first.
+		if curr.Right != nil {
+			stack = append(stack, curr.Right)
+		}
+		if curr.Left != nil {
+			stack = append(stack, curr.Left)
+		}
+	}
+}
+
+// PostOrderWalk calls fn for each node after its children.
+func (n *Node) PostOrderWalk(fn WalkFn) {
+	postOrder(n, func(curr *Node) bool {
+		return fn(curr.Key, curr.Value)
+	})
+}
+
+// postOrder calls fn for each node after its children until it
+// returns false.
+func postOrder(n *Node, fn func(*Node) bool) {
+	var stack []*Node
+	var last *Node
+	curr := n
+	for curr != nil || len(stack) > 0 {
+		for curr != nil {
+			stack = append(stack, curr)
+			curr = curr.Left
+		}
+
+		top := stack[len(stack)-1]
+		if top.Right != nil && top.Right != last {
+			// Visit the right subtree before coming back to top.
+			curr = top.Right
+			continue
+		}
+
+		stack = stack[:len(stack)-1]
+		if !fn(top) {
+			return
+		}
+		last = top
+	}
+}
+
+type levelEntry struct {
+	node  *Node
+	depth int
+}
+
+// LevelOrderTraverse calls LevelVisitor for each node a level at a
+// time, from the root down and from left to right across each level.
+func (n *Node) LevelOrderTraverse(v LevelVisitor) {
+	if n == nil {
+		return
+	}
+
+	var q queue.ArrQueue
+	q.Enqueue(levelEntry{n, 0})
+	for q.Len() > 0 {
+		e := q.Dequeue().(levelEntry)
+		if !v(e.node.Key, e.node.Value, e.depth) {
+			return
+		}
+
+		if e.node.Left != nil {
+			q.Enqueue(levelEntry{e.node.Left, e.depth + 1})
+		}
+		if e.node.Right != nil {
+			q.Enqueue(levelEntry{e.node.Right, e.depth + 1})
+		}
+	}
+}
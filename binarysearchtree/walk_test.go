@@ -0,0 +1,143 @@
+package binarysearchtree
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestWalkStops(t *testing.T) {
+	tests := map[string]struct {
+		walk     func(*Node, WalkFn)
+		expected []string
+	}{
+		"InOrder":   {(*Node).InOrderWalk, []string{"1", "2", "3", "4"}},
+		"PreOrder":  {(*Node).PreOrderWalk, []string{"8", "4", "2", "1"}},
+		"PostOrder": {(*Node).PostOrderWalk, []string{"1", "3", "2", "5"}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := &Node{Key: 8, Value: "8"}
+			fillTree(root)
+
+			var result []string
+			test.walk(root, func(key int, value interface{}) bool {
+				result = append(result, value.(string))
+				return len(result) < 4
+			})
+			if !isSameSlice(result, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestWalkEmpty(t *testing.T) {
+	var root *Node
+	fn := func(key int, value interface{}) bool {
+		t.Fatal("unexpected visit")
+		return true
+	}
+	root.InOrderWalk(fn)
+	root.PreOrderWalk(fn)
+	root.PostOrderWalk(fn)
+	root.LevelOrderTraverse(func(key int, value interface{}, depth int) bool {
+		t.Fatal("unexpected visit")
+		return true
+	})
+}
+
+func TestLevelOrderTraverse(t *testing.T) {
+	root := &Node{Key: 8, Value: "8"}
+	fillTree(root)
+
+	var result []string
+	root.LevelOrderTraverse(func(key int, value interface{}, depth int) bool {
+		result = append(result, fmt.Sprintf("%d:%d", depth, key))
+		return true
+	})
+	expected := []string{"0:8", "1:4", "1:10", "2:2", "2:6", "2:9", "2:11", "3:1", "3:3", "3:5", "3:7"}
+	if !isSameSlice(result, expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+
+	result = nil
+	root.LevelOrderTraverse(func(key int, value interface{}, depth int) bool {
+		result = append(result, fmt.Sprint(key))
+		return depth < 2
+	})
+	if expected := []string{"8", "4", "10", "2"}; !isSameSlice(result, expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+}
+
+// chain returns a degenerate tree of n nodes where every node is the
+// right child of its parent, or the left if left is set. It is linked
+// by hand because inserting into it would take quadratic time.
+func chain(n int, left bool) *Node {
+	var root *Node
+	for i := 0; i < n; i++ {
+		// Build from the leaf up, so the keys shrink as the chain
+		// grows to the right and grow as it grows to the left.
+		key := n - 1 - i
+		if left {
+			key = i
+		}
+		next := &Node{Key: key, Value: key}
+		if left {
+			next.Left = root
+		} else {
+			next.Right = root
+		}
+		root = next
+	}
+	return root
+}
+
+func TestDegenerateTraversals(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping deep trees in short mode")
+	}
+
+	const n = 1 << 20
+	for _, left := range []bool{false, true} {
+		root := chain(n, left)
+
+		walks := map[string]func(WalkFn){
+			"InOrder":   root.InOrderWalk,
+			"PreOrder":  root.PreOrderWalk,
+			"PostOrder": root.PostOrderWalk,
+		}
+		for name, walk := range walks {
+			count := 0
+			walk(func(key int, value interface{}) bool {
+				count++
+				return true
+			})
+			if count != n {
+				t.Fatalf("%s left=%v: expected %d visits, got %d", name, left, n, count)
+			}
+		}
+
+		maxDepth := 0
+		root.LevelOrderTraverse(func(key int, value interface{}, depth int) bool {
+			maxDepth = depth
+			return true
+		})
+		if maxDepth != n-1 {
+			t.Fatalf("left=%v: expected depth %d, got %d", left, n-1, maxDepth)
+		}
+
+		it := root.Iterator()
+		prev := -1
+		for it.Next() {
+			if it.Key() != prev+1 {
+				t.Fatalf("left=%v: expected %d, got %d", left, prev+1, it.Key())
+			}
+			prev = it.Key()
+		}
+		if prev != n-1 {
+			t.Fatalf("left=%v: expected to end at %d, got %d", left, n-1, prev)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+// Package btree implements an in-memory B-tree. Each node holds many
+// sorted keys in a contiguous slice, so a lookup touches far fewer
+// cache lines than it would walking the nodes of a binary tree.
+package btree
+
+import "github.com/iainanderson83/datastructures/binarysearchtree"
+
+var _ binarysearchtree.OrderedMap = &BTree{}
+
+// DefaultDegree is a reasonable minimum degree for most workloads.
+const DefaultDegree = 32
+
+// Iterator is called for each key during iteration,
+// which stops as soon as it returns false.
+type Iterator func(key int, value interface{}) bool
+
+// BTree is a B-tree of minimum degree t. Every node except the root
+// holds between t-1 and 2t-1 keys, and every leaf is the same depth.
+type BTree struct {
+	degree int
+	root   *node
+	size   int
+	cow    *owner
+}
+
+// New returns an empty tree with the given minimum degree,
+// which must be at least 2.
+func New(degree int) *BTree {
+	if degree < 2 {
+		panic("invalid degree")
+	}
+	return &BTree{degree: degree, cow: &owner{}}
+}
+
+// Degree returns the minimum degree of the tree.
+func (t *BTree) Degree() int {
+	return t.degree
+}
+
+// Len returns the number of keys in the tree.
+func (t *BTree) Len() int {
+	return t.size
+}
+
+func (t *BTree) maxItems() int {
+	return 2*t.degree - 1
+}
+
+func (t *BTree) minItems() int {
+	return t.degree - 1
+}
+
+// Insert adds the given key and value to the tree, replacing
+// the value if the key already exists.
+func (t *BTree) Insert(key int, value interface{}) {
+	it := item{key, value}
+	if t.root == nil {
+		t.root = t.cow.newNode()
+		t.root.items = append(t.root.items, it)
+		t.size++
+		return
+	}
+
+	// Split a full root before descending so that the
+	// insert never has to split on the way back up.
+	t.root = t.root.mutableFor(t.cow)
+	if len(t.root.items) >= t.maxItems() {
+		mid, right := t.root.split(t.maxItems() / 2)
+		left := t.root
+		t.root = t.cow.newNode()
+		t.root.items = append(t.root.items, mid)
+		t.root.children = append(t.root.children, left, right)
+	}
+
+	if t.root.insert(it, t.maxItems()) {
+		t.size++
+	}
+}
+
+// Get returns the value associated with the key.
+func (t *BTree) Get(key int) (interface{}, bool) {
+	for n := t.root; n != nil; {
+		i, found := n.items.find(key)
+		if found {
+			return n.items[i].value, true
+		}
+		if len(n.children) == 0 {
+			break
+		}
+		n = n.children[i]
+	}
+	return nil, false
+}
+
+// Delete removes the key from the tree and returns
+// the value it was associated with.
+func (t *BTree) Delete(key int) (interface{}, bool) {
+	if t.root == nil {
+		return nil, false
+	}
+
+	t.root = t.root.mutableFor(t.cow)
+	out, ok := t.root.remove(key, t.minItems())
+	if len(t.root.items) == 0 {
+		if len(t.root.children) > 0 {
+			t.root = t.root.children[0]
+		} else {
+			t.root = nil
+		}
+	}
+	if !ok {
+		return nil, false
+	}
+	t.size--
+	return out.value, true
+}
+
+// Min returns the min key in the tree and its value.
+func (t *BTree) Min() (int, interface{}, bool) {
+	if t.root == nil {
+		return 0, nil, false
+	}
+
+	n := t.root
+	for len(n.children) > 0 {
+		n = n.children[0]
+	}
+	return n.items[0].key, n.items[0].value, true
+}
+
+// Max returns the max key in the tree and its value.
+func (t *BTree) Max() (int, interface{}, bool) {
+	if t.root == nil {
+		return 0, nil, false
+	}
+
+	n := t.root
+	for len(n.children) > 0 {
+		n = n.children[len(n.children)-1]
+	}
+	it := n.items[len(n.items)-1]
+	return it.key, it.value, true
+}
+
+// Ascend calls fn for each key in ascending order.
+func (t *BTree) Ascend(fn Iterator) {
+	if t.root != nil {
+		t.root.ascend(nil, nil, fn)
+	}
+}
+
+// AscendRange calls fn for each key in the range [greaterOrEqual, lessThan)
+// in ascending order.
+func (t *BTree) AscendRange(greaterOrEqual, lessThan int, fn Iterator) {
+	if t.root != nil {
+		t.root.ascend(&greaterOrEqual, &lessThan, fn)
+	}
+}
+
+// Descend calls fn for each key in descending order.
+func (t *BTree) Descend(fn Iterator) {
+	if t.root != nil {
+		t.root.descend(fn)
+	}
+}
+
+// InOrderTraverse calls Visitor for each key in ascending order.
+func (t *BTree) InOrderTraverse(v binarysearchtree.Visitor) {
+	t.Ascend(func(key int, value interface{}) bool {
+		v(key, value)
+		return true
+	})
+}
+
+// Clone returns a copy of the tree in O(1). The nodes are shared
+// until either tree writes to them, at which point the writer takes
+// its own copy of each node on the path it modifies.
+func (t *BTree) Clone() *BTree {
+	// Neither tree may write to the shared nodes in place,
+	// so both are given a new owner.
+	c := *t
+	c.cow = &owner{}
+	t.cow = &owner{}
+	return &c
+}
+
+// Clear removes every key from the tree.
+func (t *BTree) Clear() {
+	t.root = nil
+	t.size = 0
+}
@@ -0,0 +1,310 @@
+package btree
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// check validates the shape of the tree: every node except the root
+// holds between degree-1 and 2*degree-1 ordered keys, every internal
+// node has one more child than it has keys, and all leaves are at the
+// same depth.
+func check(t *testing.T, tr *BTree) {
+	t.Helper()
+
+	leafDepth := -1
+	count := 0
+	var walk func(n *node, depth int, lo, hi *int)
+	walk = func(n *node, depth int, lo, hi *int) {
+		if n != tr.root && (len(n.items) < tr.minItems() || len(n.items) > tr.maxItems()) {
+			t.Fatalf("node at depth %d has %d items", depth, len(n.items))
+		}
+		for i, it := range n.items {
+			if (lo != nil && it.key <= *lo) || (hi != nil && it.key >= *hi) ||
+				(i > 0 && it.key <= n.items[i-1].key) {
+				t.Fatalf("key %d is out of order", it.key)
+			}
+		}
+		count += len(n.items)
+
+		if len(n.children) == 0 {
+			if leafDepth == -1 {
+				leafDepth = depth
+			} else if leafDepth != depth {
+				t.Fatalf("leaves at depths %d and %d", leafDepth, depth)
+			}
+			return
+		}
+
+		if len(n.children) != len(n.items)+1 {
+			t.Fatalf("node has %d items and %d children", len(n.items), len(n.children))
+		}
+		for i, c := range n.children {
+			clo, chi := lo, hi
+			if i > 0 {
+				clo = &n.items[i-1].key
+			}
+			if i < len(n.items) {
+				chi = &n.items[i].key
+			}
+			walk(c, depth+1, clo, chi)
+		}
+	}
+
+	if tr.root != nil {
+		walk(tr.root, 0, nil, nil)
+	}
+	if count != tr.Len() {
+		t.Fatalf("expected %d keys, counted %d", tr.Len(), count)
+	}
+}
+
+func ascending(tr *BTree) []int {
+	var keys []int
+	tr.Ascend(func(key int, value interface{}) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+func TestEmpty(t *testing.T) {
+	tr := New(2)
+	if _, ok := tr.Get(1); ok {
+		t.Fatal("expected no value")
+	}
+	if _, ok := tr.Delete(1); ok {
+		t.Fatal("expected nothing to delete")
+	}
+	if _, _, ok := tr.Min(); ok {
+		t.Fatal("expected no min")
+	}
+	if _, _, ok := tr.Max(); ok {
+		t.Fatal("expected no max")
+	}
+	if keys := ascending(tr); len(keys) != 0 {
+		t.Fatalf("expected no keys, got %v", keys)
+	}
+}
+
+func TestInvalidDegree(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	New(1)
+}
+
+func TestRandom(t *testing.T) {
+	for _, degree := range []int{2, 3, 4, 16} {
+		t.Run(fmt.Sprint(degree), func(t *testing.T) {
+			r := rand.New(rand.NewSource(int64(degree)))
+			tr := New(degree)
+			model := make(map[int]int)
+
+			for i := 0; i < 10000; i++ {
+				key := r.Intn(1000)
+				if r.Intn(3) == 0 {
+					v, ok := tr.Delete(key)
+					mv, mok := model[key]
+					if ok != mok || (ok && v != mv) {
+						t.Fatalf("delete %d: expected %v %v, got %v %v", key, mv, mok, v, ok)
+					}
+					delete(model, key)
+				} else {
+					tr.Insert(key, i)
+					model[key] = i
+				}
+
+				if i%100 == 0 {
+					check(t, tr)
+				}
+			}
+			check(t, tr)
+
+			for k, mv := range model {
+				if v, ok := tr.Get(k); !ok || v != mv {
+					t.Fatalf("get %d: expected %v, got %v", k, mv, v)
+				}
+			}
+
+			keys := make([]int, 0, len(model))
+			for k := range model {
+				keys = append(keys, k)
+			}
+			sort.Ints(keys)
+			if got := ascending(tr); fmt.Sprint(got) != fmt.Sprint(keys) {
+				t.Fatalf("expected %v, got %v", keys, got)
+			}
+
+			for _, k := range keys {
+				tr.Delete(k)
+			}
+			check(t, tr)
+			if tr.Len() != 0 || tr.root != nil {
+				t.Fatalf("expected an empty tree, got %d keys", tr.Len())
+			}
+		})
+	}
+}
+
+func TestIteration(t *testing.T) {
+	tr := New(2)
+	for i := 0; i < 20; i++ {
+		tr.Insert(i, i)
+	}
+
+	tests := map[string]struct {
+		iterate  func(Iterator)
+		expected string
+	}{
+		"Ascend": {
+			tr.Ascend,
+			"[0 1 2 3 4 5 6 7 8 9]",
+		},
+		"Descend": {
+			tr.Descend,
+			"[19 18 17 16 15 14 13 12 11 10]",
+		},
+		"AscendRange": {
+			func(fn Iterator) { tr.AscendRange(5, 12, fn) },
+			"[5 6 7 8 9 10 11]",
+		},
+		"AscendRangeEmpty": {
+			func(fn Iterator) { tr.AscendRange(5, 5, fn) },
+			"[]",
+		},
+		"AscendRangeOutside": {
+			func(fn Iterator) { tr.AscendRange(-10, 3, fn) },
+			"[0 1 2]",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := []int{}
+			test.iterate(func(key int, value interface{}) bool {
+				result = append(result, key)
+				return len(result) < 10
+			})
+			if fmt.Sprint(result) != test.expected {
+				t.Fatalf("expected %s, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	tr := New(3)
+	for _, k := range rand.New(rand.NewSource(1)).Perm(100) {
+		tr.Insert(k, k)
+	}
+
+	if k, v, _ := tr.Min(); k != 0 || v != 0 {
+		t.Fatalf("expected min 0, got %d", k)
+	}
+	if k, v, _ := tr.Max(); k != 99 || v != 99 {
+		t.Fatalf("expected max 99, got %d", k)
+	}
+}
+
+func TestClone(t *testing.T) {
+	tr := New(2)
+	for i := 0; i < 100; i++ {
+		tr.Insert(i, i)
+	}
+	before := fmt.Sprint(ascending(tr))
+
+	c := tr.Clone()
+	for i := 0; i < 100; i += 2 {
+		c.Delete(i)
+	}
+	for i := 100; i < 150; i++ {
+		c.Insert(i, i)
+	}
+	c.Insert(1, "changed")
+
+	check(t, tr)
+	check(t, c)
+	if got := fmt.Sprint(ascending(tr)); got != before {
+		t.Fatalf("original changed by writes to the clone: %s", got)
+	}
+	if v, _ := tr.Get(1); v != 1 {
+		t.Fatalf("expected 1, got %v", v)
+	}
+	if v, _ := c.Get(1); v != "changed" {
+		t.Fatalf("expected changed, got %v", v)
+	}
+	if c.Len() != 100 {
+		t.Fatalf("expected 100 keys in the clone, got %d", c.Len())
+	}
+
+	// Writes to the original must not show through the clone either.
+	cbefore := fmt.Sprint(ascending(c))
+	for i := 0; i < 100; i++ {
+		tr.Delete(i)
+	}
+	check(t, tr)
+	if tr.Len() != 0 {
+		t.Fatalf("expected an empty tree, got %d keys", tr.Len())
+	}
+	if got := fmt.Sprint(ascending(c)); got != cbefore {
+		t.Fatalf("clone changed by writes to the original: %s", got)
+	}
+}
+
+func BenchmarkInsert(b *testing.B) {
+	keys := rand.New(rand.NewSource(1)).Perm(1 << 16)
+
+	for _, degree := range []int{2, 4, 8, 16, 32, 64, 128} {
+		b.Run(fmt.Sprint(degree), func(b *testing.B) {
+			tr := New(degree)
+			for i := 0; i < b.N; i++ {
+				if i%len(keys) == 0 {
+					tr.Clear()
+				}
+				tr.Insert(keys[i%len(keys)], i)
+			}
+		})
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	keys := rand.New(rand.NewSource(1)).Perm(1 << 16)
+
+	for _, degree := range []int{2, 4, 8, 16, 32, 64, 128} {
+		tr := New(degree)
+		for _, k := range keys {
+			tr.Insert(k, k)
+		}
+
+		b.Run(fmt.Sprint(degree), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tr.Get(keys[i%len(keys)])
+			}
+		})
+	}
+}
+
+func BenchmarkDelete(b *testing.B) {
+	keys := rand.New(rand.NewSource(1)).Perm(1 << 16)
+
+	for _, degree := range []int{2, 4, 8, 16, 32, 64, 128} {
+		b.Run(fmt.Sprint(degree), func(b *testing.B) {
+			tr := New(degree)
+			for i := 0; i < b.N; i++ {
+				if i%len(keys) == 0 {
+					b.StopTimer()
+					for _, k := range keys {
+						tr.Insert(k, k)
+					}
+					b.StartTimer()
+				}
+				tr.Delete(keys[i%len(keys)])
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package btree
+
+import "sort"
+
+type item struct {
+	key   int
+	value interface{}
+}
+
+// owner marks the nodes a tree may modify in place. Nodes created
+// before a Clone belong to an owner neither tree holds any more.
+type owner struct {
+	// A zero size struct could share its address with other
+	// zero size values, which would make owners compare equal.
+	_ byte
+}
+
+func (o *owner) newNode() *node {
+	return &node{cow: o}
+}
+
+type node struct {
+	items    items
+	children children
+	cow      *owner
+}
+
+// mutableFor returns n if it belongs to o and a copy of n owned by o otherwise.
+func (n *node) mutableFor(o *owner) *node {
+	if n.cow == o {
+		return n
+	}
+
+	c := o.newNode()
+	c.items = append(make(items, 0, cap(n.items)), n.items...)
+	if len(n.children) > 0 {
+		c.children = append(make(children, 0, cap(n.children)), n.children...)
+	}
+	return c
+}
+
+func (n *node) mutableChild(i int) *node {
+	c := n.children[i].mutableFor(n.cow)
+	n.children[i] = c
+	return c
+}
+
+// split moves the items after i, and the children after them,
+// into a new node and returns item i along with the new node.
+func (n *node) split(i int) (item, *node) {
+	it := n.items[i]
+	next := n.cow.newNode()
+	next.items = append(next.items, n.items[i+1:]...)
+	n.items.truncate(i)
+	if len(n.children) > 0 {
+		next.children = append(next.children, n.children[i+1:]...)
+		n.children.truncate(i + 1)
+	}
+	return it, next
+}
+
+// maybeSplitChild splits child i if it is full and returns whether it did.
+func (n *node) maybeSplitChild(i, maxItems int) bool {
+	if len(n.children[i].items) < maxItems {
+		return false
+	}
+
+	first := n.mutableChild(i)
+	it, second := first.split(maxItems / 2)
+	n.items.insertAt(i, it)
+	n.children.insertAt(i+1, second)
+	return true
+}
+
+// insert adds it to the subtree rooted at n, which must not be full,
+// and returns true if the key was not already present.
+func (n *node) insert(it item, maxItems int) bool {
+	i, found := n.items.find(it.key)
+	if found {
+		n.items[i].value = it.value
+		return false
+	}
+	if len(n.children) == 0 {
+		n.items.insertAt(i, it)
+		return true
+	}
+
+	if n.maybeSplitChild(i, maxItems) {
+		switch mid := n.items[i]; {
+		case it.key > mid.key:
+			i++
+		case it.key == mid.key:
+			n.items[i].value = it.value
+			return false
+		}
+	}
+	return n.mutableChild(i).insert(it, maxItems)
+}
+
+// remove deletes the key from the subtree rooted at n. Any child it
+// descends into is first given more than minItems items, so removing
+// one never leaves a node too small.
+func (n *node) remove(key, minItems int) (item, bool) {
+	i, found := n.items.find(key)
+	if len(n.children) == 0 {
+		if !found {
+			return item{}, false
+		}
+		return n.items.removeAt(i), true
+	}
+
+	if len(n.children[i].items) <= minItems {
+		n.growChild(i, minItems)
+		return n.remove(key, minItems)
+	}
+
+	child := n.mutableChild(i)
+	if found {
+		// Replace the key with its predecessor, which is in a leaf.
+		out := n.items[i]
+		n.items[i] = child.removeMax(minItems)
+		return out, true
+	}
+	return child.remove(key, minItems)
+}
+
+func (n *node) removeMax(minItems int) item {
+	if len(n.children) == 0 {
+		return n.items.pop()
+	}
+
+	i := len(n.items)
+	if len(n.children[i].items) <= minItems {
+		n.growChild(i, minItems)
+		return n.removeMax(minItems)
+	}
+	return n.mutableChild(i).removeMax(minItems)
+}
+
+// growChild gives child i another item, either by rotating one through
+// n from a sibling with items to spare or by merging it with a sibling.
+func (n *node) growChild(i, minItems int) {
+	switch {
+	case i > 0 && len(n.children[i-1].items) > minItems:
+		child := n.mutableChild(i)
+		left := n.mutableChild(i - 1)
+		child.items.insertAt(0, n.items[i-1])
+		n.items[i-1] = left.items.pop()
+		if len(left.children) > 0 {
+			child.children.insertAt(0, left.children.pop())
+		}
+	case i < len(n.items) && len(n.children[i+1].items) > minItems:
+		child := n.mutableChild(i)
+		right := n.mutableChild(i + 1)
+		child.items = append(child.items, n.items[i])
+		n.items[i] = right.items.removeAt(0)
+		if len(right.children) > 0 {
+			child.children = append(child.children, right.children.removeAt(0))
+		}
+	default:
+		if i >= len(n.items) {
+			i--
+		}
+		child := n.mutableChild(i)
+		// The merged sibling is only read, so it needn't be copied.
+		merged := n.children.removeAt(i + 1)
+		child.items = append(child.items, n.items.removeAt(i))
+		child.items = append(child.items, merged.items...)
+		child.children = append(child.children, merged.children...)
+	}
+}
+
+// ascend calls fn for each key in [lo, hi) in ascending order,
+// where nil bounds are unbounded, and returns false if fn stopped it.
+func (n *node) ascend(lo, hi *int, fn Iterator) bool {
+	i := 0
+	if lo != nil {
+		i, _ = n.items.find(*lo)
+	}
+
+	for ; i < len(n.items); i++ {
+		if len(n.children) > 0 && !n.children[i].ascend(lo, hi, fn) {
+			return false
+		}
+		it := n.items[i]
+		if hi != nil && it.key >= *hi {
+			return false
+		}
+		if !fn(it.key, it.value) {
+			return false
+		}
+	}
+
+	if len(n.children) > 0 {
+		return n.children[len(n.children)-1].ascend(lo, hi, fn)
+	}
+	return true
+}
+
+func (n *node) descend(fn Iterator) bool {
+	for i := len(n.items) - 1; i >= 0; i-- {
+		if len(n.children) > 0 && !n.children[i+1].descend(fn) {
+			return false
+		}
+		if !fn(n.items[i].key, n.items[i].value) {
+			return false
+		}
+	}
+
+	if len(n.children) > 0 {
+		return n.children[0].descend(fn)
+	}
+	return true
+}
+
+type items []item
+
+// find returns the index of the first item not less than key
+// and whether that item holds the key.
+func (s items) find(key int) (int, bool) {
+	i := sort.Search(len(s), func(i int) bool {
+		return s[i].key >= key
+	})
+	return i, i < len(s) && s[i].key == key
+}
+
+func (s *items) insertAt(i int, it item) {
+	*s = append(*s, item{})
+	copy((*s)[i+1:], (*s)[i:])
+	(*s)[i] = it
+}
+
+func (s *items) removeAt(i int) item {
+	it := (*s)[i]
+	copy((*s)[i:], (*s)[i+1:])
+	(*s)[len(*s)-1] = item{}
+	*s = (*s)[:len(*s)-1]
+	return it
+}
+
+func (s *items) pop() item {
+	return s.removeAt(len(*s) - 1)
+}
+
+// truncate shortens the slice to i items, clearing the
+// rest so that the values they hold can be collected.
+func (s *items) truncate(i int) {
+	for j := i; j < len(*s); j++ {
+		(*s)[j] = item{}
+	}
+	*s = (*s)[:i]
+}
+
+type children []*node
+
+func (s *children) insertAt(i int, n *node) {
+	*s = append(*s, nil)
+	copy((*s)[i+1:], (*s)[i:])
+	(*s)[i] = n
+}
+
+func (s *children) removeAt(i int) *node {
+	n := (*s)[i]
+	copy((*s)[i:], (*s)[i+1:])
+	(*s)[len(*s)-1] = nil
+	*s = (*s)[:len(*s)-1]
+	return n
+}
+
+func (s *children) pop() *node {
+	return s.removeAt(len(*s) - 1)
+}
+
+func (s *children) truncate(i int) {
+	for j := i; j < len(*s); j++ {
+		(*s)[j] = nil
+	}
+	*s = (*s)[:i]
+}
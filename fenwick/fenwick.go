@@ -0,0 +1,83 @@
+// Package fenwick implements Fenwick trees, also known as binary
+// indexed trees, which keep the prefix sums of an array up to date as
+// it changes, in O(log n) per update or query and with no memory
+// beyond a single slice the length of the array.
+package fenwick
+
+// Number is a type which supports addition and subtraction.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// Tree is a Fenwick tree with point updates and range sums. Indexes
+// count from zero and ranges are half open, so Sum(lo, hi) covers the
+// values at lo up to but not including hi.
+type Tree[T Number] struct {
+	// sums[i-1] holds the sum of the values in (i - i&-i, i], counting
+	// from one as the lowest set bit of i gives the span.
+	sums []T
+}
+
+// New returns a tree of n zeros.
+func New[T Number](n int) *Tree[T] {
+	return &Tree[T]{sums: make([]T, n)}
+}
+
+// FromSlice returns a tree holding the values, built in O(n).
+func FromSlice[T Number](values []T) *Tree[T] {
+	sums := append([]T(nil), values...)
+	for i := 1; i <= len(sums); i++ {
+		// Add each span to the next span which covers it.
+		if parent := i + i&-i; parent <= len(sums) {
+			sums[parent-1] += sums[i-1]
+		}
+	}
+	return &Tree[T]{sums: sums}
+}
+
+// Len returns the number of values in the tree.
+func (t *Tree[T]) Len() int {
+	return len(t.sums)
+}
+
+// Add adds delta to the value at index i.
+func (t *Tree[T]) Add(i int, delta T) {
+	if i < 0 || i >= len(t.sums) {
+		panic("index out of range")
+	}
+	for i++; i <= len(t.sums); i += i & -i {
+		t.sums[i-1] += delta
+	}
+}
+
+// Set replaces the value at index i.
+func (t *Tree[T]) Set(i int, value T) {
+	t.Add(i, value-t.Get(i))
+}
+
+// Get returns the value at index i.
+func (t *Tree[T]) Get(i int) T {
+	return t.Sum(i, i+1)
+}
+
+// PrefixSum returns the sum of the first n values.
+func (t *Tree[T]) PrefixSum(n int) T {
+	if n < 0 || n > len(t.sums) {
+		panic("index out of range")
+	}
+	var sum T
+	for ; n > 0; n -= n & -n {
+		sum += t.sums[n-1]
+	}
+	return sum
+}
+
+// Sum returns the sum of the values between lo and hi.
+func (t *Tree[T]) Sum(lo, hi int) T {
+	if lo > hi {
+		panic("range out of bounds")
+	}
+	return t.PrefixSum(hi) - t.PrefixSum(lo)
+}
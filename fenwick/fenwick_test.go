@@ -0,0 +1,177 @@
+package fenwick
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func sum(values []int) int {
+	s := 0
+	for _, v := range values {
+		s += v
+	}
+	return s
+}
+
+func TestTree(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 2, 3, 8, 13, 100} {
+		values := make([]int, n)
+		for i := range values {
+			values[i] = r.Intn(201) - 100
+		}
+		tr := FromSlice(values)
+		if tr.Len() != n {
+			t.Fatalf("expected %d values, got %d", n, tr.Len())
+		}
+
+		for op := 0; op < 2000; op++ {
+			lo := r.Intn(n + 1)
+			hi := lo + r.Intn(n-lo+1)
+			switch r.Intn(4) {
+			case 0:
+				if n == 0 {
+					continue
+				}
+				i, delta := r.Intn(n), r.Intn(21)-10
+				tr.Add(i, delta)
+				values[i] += delta
+			case 1:
+				if n == 0 {
+					continue
+				}
+				i, v := r.Intn(n), r.Intn(201)-100
+				tr.Set(i, v)
+				values[i] = v
+			default:
+				if got, expected := tr.Sum(lo, hi), sum(values[lo:hi]); got != expected {
+					t.Fatalf("n=%d op=%d: sum [%d, %d): expected %d, got %d", n, op, lo, hi, expected, got)
+				}
+				if got, expected := tr.PrefixSum(hi), sum(values[:hi]); got != expected {
+					t.Fatalf("n=%d op=%d: prefix sum %d: expected %d, got %d", n, op, hi, expected, got)
+				}
+			}
+		}
+
+		for i, v := range values {
+			if got := tr.Get(i); got != v {
+				t.Fatalf("n=%d: index %d: expected %d, got %d", n, i, v, got)
+			}
+		}
+	}
+}
+
+func TestFromSlice(t *testing.T) {
+	values := []float64{1.5, -2, 0, 4.25, 8, 3, -1, 7, 2}
+	built := FromSlice(values)
+	added := New[float64](len(values))
+	for i, v := range values {
+		added.Add(i, v)
+	}
+
+	for n := 0; n <= len(values); n++ {
+		if b, a := built.PrefixSum(n), added.PrefixSum(n); b != a {
+			t.Fatalf("prefix sum %d: expected %v, got %v", n, a, b)
+		}
+	}
+}
+
+func TestRangeTree(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 2, 3, 8, 13, 100} {
+		values := make([]int, n)
+		for i := range values {
+			values[i] = r.Intn(201) - 100
+		}
+		tr := RangeFromSlice(values)
+		if tr.Len() != n {
+			t.Fatalf("expected %d values, got %d", n, tr.Len())
+		}
+
+		for op := 0; op < 2000; op++ {
+			lo := r.Intn(n + 1)
+			hi := lo + r.Intn(n-lo+1)
+			switch r.Intn(4) {
+			case 0:
+				delta := r.Intn(21) - 10
+				tr.AddRange(lo, hi, delta)
+				for i := lo; i < hi; i++ {
+					values[i] += delta
+				}
+			case 1:
+				if n == 0 {
+					continue
+				}
+				i, delta := r.Intn(n), r.Intn(21)-10
+				tr.Add(i, delta)
+				values[i] += delta
+			default:
+				if got, expected := tr.Sum(lo, hi), sum(values[lo:hi]); got != expected {
+					t.Fatalf("n=%d op=%d: sum [%d, %d): expected %d, got %d", n, op, lo, hi, expected, got)
+				}
+			}
+		}
+
+		for i, v := range values {
+			if got := tr.Get(i); got != v {
+				t.Fatalf("n=%d: index %d: expected %d, got %d", n, i, v, got)
+			}
+		}
+	}
+
+	// An empty range tree starts at zero.
+	empty := NewRange[int](5)
+	empty.AddRange(1, 4, 2)
+	if got := empty.Sum(0, 5); got != 6 {
+		t.Fatalf("expected 6, got %d", got)
+	}
+}
+
+func TestPanics(t *testing.T) {
+	tr := New[int](3)
+	rt := NewRange[int](3)
+
+	tests := map[string]func(){
+		"AddNegative":      func() { tr.Add(-1, 1) },
+		"AddPastEnd":       func() { tr.Add(3, 1) },
+		"PrefixPastEnd":    func() { tr.PrefixSum(4) },
+		"SumReversed":      func() { tr.Sum(2, 1) },
+		"RangeAddPastEnd":  func() { rt.AddRange(1, 4, 1) },
+		"RangeAddReversed": func() { rt.AddRange(2, 1, 1) },
+		"RangeGetPastEnd":  func() { rt.Get(3) },
+	}
+	for name, fn := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected a panic")
+				}
+			}()
+			fn()
+		})
+	}
+}
+
+func BenchmarkFenwick(b *testing.B) {
+	const n = 1 << 20
+	r := rand.New(rand.NewSource(1))
+	tr := New[int](n)
+	rt := NewRange[int](n)
+
+	benchmarks := []struct {
+		name string
+		fn   func()
+	}{
+		{"Add", func() { tr.Add(r.Intn(n), 1) }},
+		{"Sum", func() { lo := r.Intn(n); tr.Sum(lo, lo+r.Intn(n-lo)) }},
+		{"RangeAdd", func() { lo := r.Intn(n); rt.AddRange(lo, lo+r.Intn(n-lo), 1) }},
+		{"RangeSum", func() { lo := r.Intn(n); rt.Sum(lo, lo+r.Intn(n-lo)) }},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				bm.fn()
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package fenwick
+
+// RangeTree is a Fenwick tree which adds to whole ranges of values as
+// well as summing them, both in O(log n). It keeps two trees: the
+// prefix sum of the first n values is n*a(n) - b(n), where a and b are
+// the prefix sums of the two, and adding d to the values from lo up to
+// hi adds d to a at lo and takes it away at hi, while adjusting b by
+// d*lo and d*hi so the sums before lo and after hi are unchanged.
+type RangeTree[T Number] struct {
+	a, b *Tree[T]
+}
+
+// NewRange returns a range tree of n zeros.
+func NewRange[T Number](n int) *RangeTree[T] {
+	return &RangeTree[T]{a: New[T](n + 1), b: New[T](n + 1)}
+}
+
+// RangeFromSlice returns a range tree holding the values, built in O(n).
+func RangeFromSlice[T Number](values []T) *RangeTree[T] {
+	// Each value is an update of the single value range [i, i+1).
+	a := make([]T, len(values)+1)
+	b := make([]T, len(values)+1)
+	for i, v := range values {
+		a[i] += v
+		a[i+1] -= v
+		b[i] += v * T(i)
+		b[i+1] -= v * T(i+1)
+	}
+	return &RangeTree[T]{a: FromSlice(a), b: FromSlice(b)}
+}
+
+// Len returns the number of values in the tree.
+func (t *RangeTree[T]) Len() int {
+	return t.a.Len() - 1
+}
+
+// AddRange adds delta to every value between lo and hi.
+func (t *RangeTree[T]) AddRange(lo, hi int, delta T) {
+	if lo < 0 || hi > t.Len() || lo > hi {
+		panic("range out of bounds")
+	}
+	t.a.Add(lo, delta)
+	t.a.Add(hi, -delta)
+	t.b.Add(lo, delta*T(lo))
+	t.b.Add(hi, -delta*T(hi))
+}
+
+// Add adds delta to the value at index i.
+func (t *RangeTree[T]) Add(i int, delta T) {
+	t.AddRange(i, i+1, delta)
+}
+
+// Get returns the value at index i.
+func (t *RangeTree[T]) Get(i int) T {
+	return t.Sum(i, i+1)
+}
+
+// PrefixSum returns the sum of the first n values.
+func (t *RangeTree[T]) PrefixSum(n int) T {
+	if n < 0 || n > t.Len() {
+		panic("index out of range")
+	}
+	return T(n)*t.a.PrefixSum(n) - t.b.PrefixSum(n)
+}
+
+// Sum returns the sum of the values between lo and hi.
+func (t *RangeTree[T]) Sum(lo, hi int) T {
+	if lo > hi {
+		panic("range out of bounds")
+	}
+	return t.PrefixSum(hi) - t.PrefixSum(lo)
+}
@@ -1,6 +1,6 @@
 module github.com/iainanderson83/datastructures
 
-go 1.13
+go 1.21
 
 require (
 	github.com/cespare/xxhash v1.1.0
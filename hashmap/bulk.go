@@ -0,0 +1,284 @@
+package hashmap
+
+import "sort"
+
+// Pair is a key/value pair.
+type Pair struct {
+	Key   string
+	Value interface{}
+}
+
+// Clone returns a copy of the map. The buckets are copied
+// as they are, so no keys are rehashed.
+func (h *Hashmap) Clone() *Hashmap {
+	h.lock.RLock()
+
+	c := &Hashmap{
+		lbound:   h.lbound,
+		ubound:   h.ubound,
+		length:   h.length,
+		fn:       h.fn,
+		strategy: h.strategy,
+		lock:     newLocker(h.strategy),
+		buckets:  make([][8]entry, len(h.buckets)),
+	}
+	copy(c.buckets, h.buckets)
+
+	h.lock.RUnlock()
+	return c
+}
+
+// Merge adds every key/value pair from other into the map and
+// returns the number of keys added. When a key exists in both maps,
+// fn decides the value kept; a nil fn keeps the value from other.
+// The map is resized at most once, however the keys hash.
+func (h *Hashmap) Merge(other *Hashmap, fn func(k string, v, ov interface{}) interface{}) int {
+	if other == h {
+		return 0
+	}
+	return h.add(other.Entries(), fn)
+}
+
+// AddAll adds the pairs to the map and returns the number of keys added.
+// The map is resized at most once, however the keys hash.
+func (h *Hashmap) AddAll(pairs []Pair) int {
+	return h.add(pairs, nil)
+}
+
+// AddMap adds the contents of the Go map to the map and
+// returns the number of keys added.
+func (h *Hashmap) AddMap(m map[string]interface{}) int {
+	pairs := make([]Pair, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, Pair{k, v})
+	}
+	return h.add(pairs, nil)
+}
+
+// add puts the pairs in the map, resizing it at most once. The new
+// length is worked out before any entry is moved: it is doubled from
+// the size needed for the incoming count until no bucket would
+// overflow, so even a poor distribution is rehashed in a single pass.
+func (h *Hashmap) add(pairs []Pair, fn func(k string, v, ov interface{}) interface{}) int {
+	h.lock.Lock()
+
+	hashes := make([]uint64, len(pairs))
+	for i := range pairs {
+		hashes[i] = h.fn(pairs[i].Key)
+	}
+
+	fresh := h.fresh(hashes)
+	length := h.length
+	if n := fit(h.count() + len(fresh)); n > length {
+		length = n
+	}
+	for !h.fits(length, fresh) {
+		length *= 2
+	}
+	if length != h.length {
+		h.length = length
+		h.resize()
+	}
+
+	var added int
+	for i := range pairs {
+		h.put(hashes[i], pairs[i].Key, pairs[i].Value, fn, &added)
+	}
+
+	h.lock.Unlock()
+	return added
+}
+
+// fresh returns the distinct hashes which aren't yet in the map,
+// the lock must be held.
+func (h *Hashmap) fresh(hashes []uint64) []uint64 {
+	sorted := append([]uint64(nil), hashes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	fresh := sorted[:0]
+	for i, hash := range sorted {
+		if i > 0 && hash == sorted[i-1] {
+			continue
+		}
+
+		idx := hash & (uint64(h.length) - 1)
+		found := false
+		for j := range h.buckets[idx] {
+			if h.buckets[idx][j].hash == hash {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fresh = append(fresh, hash)
+		}
+	}
+	return fresh
+}
+
+// fits returns whether the entries in the map and the given hashes
+// would fit in length buckets, the lock must be held. No bucket is
+// filled beyond ubound, the most Add leaves in one, so that Add
+// always finds a free slot afterwards.
+func (h *Hashmap) fits(length int, hashes []uint64) bool {
+	counts := make([]int, length)
+	place := func(hash uint64) bool {
+		idx := hash & (uint64(length) - 1)
+		counts[idx]++
+		return counts[idx] <= h.ubound
+	}
+
+	for i := range h.buckets {
+		for j := range h.buckets[i] {
+			if h.buckets[i][j].hash != 0 && !place(h.buckets[i][j].hash) {
+				return false
+			}
+		}
+	}
+	for _, hash := range hashes {
+		if !place(hash) {
+			return false
+		}
+	}
+	return true
+}
+
+// put stores the pair in its bucket, which add has made sure has room.
+func (h *Hashmap) put(hash uint64, k string, v interface{}, fn func(k string, v, ov interface{}) interface{}, added *int) {
+	idx := hash & (uint64(h.length) - 1)
+
+	target := -1
+	for i := range h.buckets[idx] {
+		if h.buckets[idx][i].hash == hash {
+			if fn != nil {
+				v = fn(k, h.buckets[idx][i].value, v)
+			}
+			h.buckets[idx][i].value = v
+			return
+		}
+
+		if h.buckets[idx][i].hash == 0 && target == -1 {
+			target = i
+		}
+	}
+
+	h.buckets[idx][target] = entry{hash: hash, key: k, value: v}
+	*added++
+}
+
+// DeleteIf removes every key/value pair for which fn returns true
+// and returns the number of keys removed.
+func (h *Hashmap) DeleteIf(fn func(k string, v interface{}) bool) int {
+	h.lock.Lock()
+
+	var deleted, remaining int
+	for i := range h.buckets {
+		for j := range h.buckets[i] {
+			if h.buckets[i][j].hash == 0 {
+				continue
+			}
+			if fn(h.buckets[i][j].key, h.buckets[i][j].value) {
+				h.buckets[i][j] = entry{}
+				deleted++
+				continue
+			}
+			remaining++
+		}
+	}
+
+	// Shrink in one pass, to the fewest buckets the rest fit in.
+	length := fit(remaining)
+	for length < h.length && !h.fits(length, nil) {
+		length *= 2
+	}
+	if length < h.length {
+		h.length = length
+		h.resize()
+	}
+
+	h.lock.Unlock()
+	return deleted
+}
+
+// Clear removes every key/value pair from the map.
+func (h *Hashmap) Clear() {
+	h.lock.Lock()
+
+	h.length = 1 << length
+	h.buckets = make([][8]entry, h.length)
+
+	h.lock.Unlock()
+}
+
+// Keys returns a snapshot of the keys in the map.
+func (h *Hashmap) Keys() []string {
+	h.lock.RLock()
+
+	keys := make([]string, 0, h.count())
+	for i := range h.buckets {
+		for j := range h.buckets[i] {
+			if h.buckets[i][j].hash != 0 {
+				keys = append(keys, h.buckets[i][j].key)
+			}
+		}
+	}
+
+	h.lock.RUnlock()
+	return keys
+}
+
+// Values returns a snapshot of the values in the map.
+func (h *Hashmap) Values() []interface{} {
+	h.lock.RLock()
+
+	values := make([]interface{}, 0, h.count())
+	for i := range h.buckets {
+		for j := range h.buckets[i] {
+			if h.buckets[i][j].hash != 0 {
+				values = append(values, h.buckets[i][j].value)
+			}
+		}
+	}
+
+	h.lock.RUnlock()
+	return values
+}
+
+// Entries returns a snapshot of the key/value pairs in the map.
+func (h *Hashmap) Entries() []Pair {
+	h.lock.RLock()
+
+	pairs := make([]Pair, 0, h.count())
+	for i := range h.buckets {
+		for j := range h.buckets[i] {
+			if h.buckets[i][j].hash != 0 {
+				pairs = append(pairs, Pair{h.buckets[i][j].key, h.buckets[i][j].value})
+			}
+		}
+	}
+
+	h.lock.RUnlock()
+	return pairs
+}
+
+// count returns the number of elements, the lock must be held.
+func (h *Hashmap) count() int {
+	var length int
+	for i := range h.buckets {
+		for j := range h.buckets[i] {
+			if h.buckets[i][j].hash > 0 {
+				length++
+			}
+		}
+	}
+	return length
+}
+
+// fit returns the number of buckets for n elements.
+func fit(n int) int {
+	size := 1 << length
+	for size < n {
+		size *= 2
+	}
+	return size
+}
@@ -0,0 +1,292 @@
+package hashmap
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func fillMap(m *Hashmap, tuples []entry) {
+	for _, tpl := range tuples {
+		m.Add(tpl.key, tpl.value)
+	}
+}
+
+func TestClone(t *testing.T) {
+	m := NewFNV1aHashmap()
+	fillMap(m, redistributionTuples)
+
+	c := m.Clone()
+	c.Add("hello", "world")
+	m.Delete(redistributionTuples[0].key)
+
+	if _, ok := m.Lookup("hello"); ok {
+		t.Fatal("clone shares buckets with original")
+	}
+	for _, tpl := range redistributionTuples {
+		v, _ := c.Lookup(tpl.key)
+		if v != tpl.value {
+			t.Fatalf("%s: expected '%v', got '%v'", tpl.key, tpl.value, v)
+		}
+	}
+	if c.Len() != len(redistributionTuples)+1 {
+		t.Fatalf("expected %d, got %d", len(redistributionTuples)+1, c.Len())
+	}
+}
+
+func TestMerge(t *testing.T) {
+	tests := map[string]struct {
+		fn       func(k string, v, ov interface{}) interface{}
+		expected interface{}
+	}{
+		"KeepOther": {
+			nil,
+			"other",
+		},
+		"KeepOriginal": {
+			func(k string, v, ov interface{}) interface{} { return v },
+			"original",
+		},
+		"Combine": {
+			func(k string, v, ov interface{}) interface{} { return v.(string) + "+" + ov.(string) },
+			"original+other",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := NewXXHashmap()
+			m.Add("shared key", "original")
+			m.Add("my key", "mine")
+
+			other := NewXXHashmap()
+			other.Add("shared key", "other")
+			fillMap(other, redistributionTuples)
+
+			if added := m.Merge(other, test.fn); added != len(redistributionTuples) {
+				t.Fatalf("expected %d added, got %d", len(redistributionTuples), added)
+			}
+			if v, _ := m.Lookup("shared key"); v != test.expected {
+				t.Fatalf("expected '%v', got '%v'", test.expected, v)
+			}
+			if v, _ := m.Lookup("my key"); v != "mine" {
+				t.Fatalf("expected 'mine', got '%v'", v)
+			}
+			for _, tpl := range redistributionTuples {
+				v, _ := m.Lookup(tpl.key)
+				if v != tpl.value {
+					t.Fatalf("%s: expected '%v', got '%v'", tpl.key, tpl.value, v)
+				}
+			}
+		})
+	}
+}
+
+func TestAddAll(t *testing.T) {
+	pairs := make([]Pair, 0, 1000)
+	gomap := make(map[string]interface{}, 1000)
+	for i := 0; i < 1000; i++ {
+		k := strconv.Itoa(i)
+		pairs = append(pairs, Pair{k, i})
+		gomap[k] = i
+	}
+
+	m := NewRuntimeHashmap()
+	if added := m.AddAll(pairs); added != 1000 {
+		t.Fatalf("expected 1000 added, got %d", added)
+	}
+
+	m2 := NewRuntimeHashmap()
+	if added := m2.AddMap(gomap); added != 1000 {
+		t.Fatalf("expected 1000 added, got %d", added)
+	}
+
+	for _, m := range []*Hashmap{m, m2} {
+		if m.Len() != 1000 {
+			t.Fatalf("expected 1000, got %d", m.Len())
+		}
+		for _, p := range pairs {
+			v, _ := m.Lookup(p.Key)
+			if v != p.Value {
+				t.Fatalf("%s: expected '%v', got '%v'", p.Key, p.Value, v)
+			}
+		}
+	}
+}
+
+// collidingHash clears the low 8 bits of every hash, so keys only
+// spread across buckets once there are more than 256 of them.
+func collidingHash(k string) uint64 {
+	i, _ := strconv.Atoi(k)
+	return uint64(i+1) << 8
+}
+
+func TestAddAllCollisions(t *testing.T) {
+	// The 100 keys need 8192 buckets to leave at most ubound in each.
+	fn := collidingHash
+
+	pairs := make([]Pair, 0, 100)
+	for i := 0; i < 100; i++ {
+		pairs = append(pairs, Pair{strconv.Itoa(i), i})
+	}
+
+	m := NewHashmap(fn)
+	m.Add("0", 0)
+	if added := m.AddAll(pairs); added != 99 {
+		t.Fatalf("expected 99 added, got %d", added)
+	}
+	if len(m.buckets) != 8192 {
+		t.Fatalf("expected 8192 buckets, got %d", len(m.buckets))
+	}
+
+	// Keys which already fit don't move the buckets at all.
+	buckets := m.buckets
+	if added := m.AddAll(pairs[:10]); added != 0 {
+		t.Fatalf("expected 0 added, got %d", added)
+	}
+	if &m.buckets[0] != &buckets[0] {
+		t.Fatal("expected the buckets to be kept")
+	}
+	for _, p := range pairs {
+		v, _ := m.Lookup(p.Key)
+		if v != p.Value {
+			t.Fatalf("%s: expected '%v', got '%v'", p.Key, p.Value, v)
+		}
+	}
+}
+
+func TestAddAfterAddAll(t *testing.T) {
+	pairs := make([]Pair, 0, 128)
+	for i := 0; i < 128; i++ {
+		pairs = append(pairs, Pair{strconv.Itoa(i), i})
+	}
+
+	// AddAll must leave room in every bucket for Add to use.
+	m := NewHashmap(collidingHash)
+	m.AddAll(pairs)
+	for i := 128; i < 256; i++ {
+		m.Add(strconv.Itoa(i), i)
+	}
+	for i := 0; i < 256; i++ {
+		if v, _ := m.Lookup(strconv.Itoa(i)); v != i {
+			t.Fatalf("%d: expected '%v', got '%v'", i, i, v)
+		}
+	}
+}
+
+func TestDeleteIf(t *testing.T) {
+	m := NewFNV1aHashmap()
+	for i := 0; i < 1000; i++ {
+		m.Add(strconv.Itoa(i), i)
+	}
+
+	deleted := m.DeleteIf(func(k string, v interface{}) bool {
+		return v.(int)%10 != 0
+	})
+	if deleted != 900 {
+		t.Fatalf("expected 900 deleted, got %d", deleted)
+	}
+	if m.Len() != 100 {
+		t.Fatalf("expected 100, got %d", m.Len())
+	}
+	for i := 0; i < 1000; i++ {
+		_, ok := m.Lookup(strconv.Itoa(i))
+		if ok != (i%10 == 0) {
+			t.Fatalf("%d: unexpected presence %v", i, ok)
+		}
+	}
+}
+
+func TestDeleteIfCollisions(t *testing.T) {
+	pairs := make([]Pair, 0, 100)
+	for i := 0; i < 100; i++ {
+		pairs = append(pairs, Pair{strconv.Itoa(i), i})
+	}
+
+	m := NewHashmap(collidingHash)
+	m.AddAll(pairs)
+	if deleted := m.DeleteIf(func(k string, v interface{}) bool { return v.(int) >= 50 }); deleted != 50 {
+		t.Fatalf("expected 50 deleted, got %d", deleted)
+	}
+
+	// The other 50 keys need 4096 buckets to leave at most ubound in each.
+	if len(m.buckets) != 4096 {
+		t.Fatalf("expected 4096 buckets, got %d", len(m.buckets))
+	}
+	for _, p := range pairs {
+		v, ok := m.Lookup(p.Key)
+		if ok != (p.Value.(int) < 50) || (ok && v != p.Value) {
+			t.Fatalf("%s: unexpected '%v' %v", p.Key, v, ok)
+		}
+	}
+	m.Add("100", 100)
+	if v, _ := m.Lookup("100"); v != 100 {
+		t.Fatalf("expected '100', got '%v'", v)
+	}
+}
+
+func TestClear(t *testing.T) {
+	m := NewFNV1aHashmap()
+	fillMap(m, redistributionTuples)
+	m.Clear()
+
+	if m.Len() != 0 {
+		t.Fatalf("expected 0, got %d", m.Len())
+	}
+	if _, ok := m.Lookup(redistributionTuples[0].key); ok {
+		t.Fatal("expected empty map")
+	}
+}
+
+func TestSnapshots(t *testing.T) {
+	m := NewXXHashmap()
+	fillMap(m, redistributionTuples)
+
+	var expectedKeys, expectedValues []string
+	for _, tpl := range redistributionTuples {
+		expectedKeys = append(expectedKeys, tpl.key)
+		expectedValues = append(expectedValues, tpl.value.(string))
+	}
+	sort.Strings(expectedKeys)
+	sort.Strings(expectedValues)
+
+	var keys, values, entries []string
+	keys = append(keys, m.Keys()...)
+	for _, v := range m.Values() {
+		values = append(values, v.(string))
+	}
+	for _, p := range m.Entries() {
+		if v, _ := m.Lookup(p.Key); v != p.Value {
+			t.Fatalf("%s: expected '%v', got '%v'", p.Key, v, p.Value)
+		}
+		entries = append(entries, p.Key)
+	}
+	sort.Strings(keys)
+	sort.Strings(values)
+	sort.Strings(entries)
+
+	if strings.Join(keys, ",") != strings.Join(expectedKeys, ",") {
+		t.Fatalf("expected keys %v, got %v", expectedKeys, keys)
+	}
+	if strings.Join(entries, ",") != strings.Join(expectedKeys, ",") {
+		t.Fatalf("expected entries %v, got %v", expectedKeys, entries)
+	}
+	if strings.Join(values, ",") != strings.Join(expectedValues, ",") {
+		t.Fatalf("expected values %v, got %v", expectedValues, values)
+	}
+}
+
+func BenchmarkAddAll(b *testing.B) {
+	pairs := make([]Pair, 0, len(redistributionTuples))
+	for _, tpl := range redistributionTuples {
+		pairs = append(pairs, Pair{tpl.key, tpl.value})
+	}
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		m := NewXXHashmap()
+		m.AddAll(pairs)
+	}
+}
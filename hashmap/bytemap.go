@@ -0,0 +1,252 @@
+package hashmap
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/cespare/xxhash"
+)
+
+const (
+	// hash, key length and value length
+	headerSize = 16
+
+	defaultChunkSize = 1 << 20
+)
+
+// ByteMap is a map of []byte keys to []byte values. Entries are
+// serialised into large pointer-free chunks and the index holds
+// no pointers, so the garbage collector never has to scan them.
+//
+// When the allocated chunks exceed the byte budget, the map is
+// compacted if enough space is held by deleted entries, otherwise
+// the oldest chunks are evicted.
+type ByteMap struct {
+	lock locker
+	fn   func([]byte) uint64
+
+	// index maps a hash to the location of its entry,
+	// the chunk id in the high bits and the offset in the low bits.
+	index  map[uint64]uint64
+	chunks [][]byte
+	first  uint64 // id of chunks[0]
+
+	chunkSize int
+	maxBytes  int
+	size      int // bytes allocated for chunks
+	dead      int // bytes held by deleted or overwritten entries
+}
+
+// NewXXByteMap returns a byte map using the xxhash
+// hashing function, bounded to maxBytes.
+func NewXXByteMap(maxBytes int) *ByteMap {
+	return NewByteMap(xxhash.Sum64, maxBytes)
+}
+
+// NewRuntimeByteMap returns a byte map using the runtime.memhash
+// hashing function, bounded to maxBytes.
+func NewRuntimeByteMap(maxBytes int) *ByteMap {
+	return NewByteMap(memHash, maxBytes)
+}
+
+// NewByteMap creates a new, empty, byte map. A maxBytes of zero
+// means the map is unbounded.
+func NewByteMap(fn func([]byte) uint64, maxBytes int) *ByteMap {
+	return newByteMapWithChunkSize(fn, maxBytes, defaultChunkSize)
+}
+
+func newByteMapWithChunkSize(fn func([]byte) uint64, maxBytes, chunkSize int) *ByteMap {
+	return &ByteMap{
+		lock:      newLocker(SpinLock),
+		fn:        fn,
+		index:     make(map[uint64]uint64),
+		chunkSize: chunkSize,
+		maxBytes:  maxBytes,
+	}
+}
+
+// Set stores a copy of the value v associated with the key k.
+// Any existing entry with the same hash is replaced.
+func (b *ByteMap) Set(k, v []byte) {
+	b.lock.Lock()
+
+	hash := b.fn(k)
+	if loc, ok := b.index[hash]; ok {
+		b.dead += len(b.entry(loc))
+	}
+	b.index[hash] = b.write(hash, k, v)
+
+	if b.maxBytes > 0 && b.size > b.maxBytes {
+		b.evict()
+	}
+
+	b.lock.Unlock()
+}
+
+// Get returns a copy of the value associated with the key k.
+func (b *ByteMap) Get(k []byte) ([]byte, bool) {
+	b.lock.RLock()
+
+	loc, ok := b.index[b.fn(k)]
+	if !ok {
+		b.lock.RUnlock()
+		return nil, false
+	}
+
+	key, value := split(b.entry(loc))
+	if !bytes.Equal(key, k) {
+		b.lock.RUnlock()
+		return nil, false
+	}
+
+	out := append([]byte(nil), value...)
+	b.lock.RUnlock()
+	return out, true
+}
+
+// Delete removes the key from the map, if it exists,
+// and returns whether or not it was deleted.
+func (b *ByteMap) Delete(k []byte) bool {
+	b.lock.Lock()
+
+	hash := b.fn(k)
+	loc, ok := b.index[hash]
+	if !ok {
+		b.lock.Unlock()
+		return false
+	}
+
+	e := b.entry(loc)
+	if key, _ := split(e); !bytes.Equal(key, k) {
+		b.lock.Unlock()
+		return false
+	}
+
+	delete(b.index, hash)
+	b.dead += len(e)
+
+	b.lock.Unlock()
+	return true
+}
+
+// Len returns the number of elements in the map.
+func (b *ByteMap) Len() int {
+	b.lock.RLock()
+
+	length := len(b.index)
+
+	b.lock.RUnlock()
+	return length
+}
+
+// Size returns the number of bytes allocated for entries.
+func (b *ByteMap) Size() int {
+	b.lock.RLock()
+
+	size := b.size
+
+	b.lock.RUnlock()
+	return size
+}
+
+// Compact rewrites the live entries into new chunks,
+// releasing the space held by deleted entries.
+func (b *ByteMap) Compact() {
+	b.lock.Lock()
+
+	b.compact()
+
+	b.lock.Unlock()
+}
+
+func (b *ByteMap) compact() {
+	old, first := b.chunks, b.first
+	b.chunks, b.first, b.size, b.dead = nil, 0, 0, 0
+
+	// Walk the chunks in order so the oldest entries are still evicted first.
+	for i := range old {
+		id := first + uint64(i)
+		for off := 0; off < len(old[i]); {
+			e := old[i][off:]
+			e = e[:entrySize(e)]
+			hash := binary.LittleEndian.Uint64(e)
+			if loc, ok := b.index[hash]; ok && loc == id<<32|uint64(off) {
+				key, value := split(e)
+				b.index[hash] = b.write(hash, key, value)
+			}
+			off += len(e)
+		}
+	}
+}
+
+func (b *ByteMap) evict() {
+	if b.dead*2 >= b.size {
+		b.compact()
+	}
+
+	// Never drop the chunk currently being written.
+	for b.size > b.maxBytes && len(b.chunks) > 1 {
+		chunk := b.chunks[0]
+		for off := 0; off < len(chunk); {
+			e := chunk[off:]
+			size := entrySize(e)
+			hash := binary.LittleEndian.Uint64(e)
+			if loc, ok := b.index[hash]; ok && loc == b.first<<32|uint64(off) {
+				delete(b.index, hash)
+			} else {
+				b.dead -= size
+			}
+			off += size
+		}
+
+		b.size -= cap(chunk)
+		b.chunks[0] = nil // GC
+		b.chunks = b.chunks[1:]
+		b.first++
+	}
+}
+
+// write appends the entry to the current chunk and returns its location.
+func (b *ByteMap) write(hash uint64, k, v []byte) uint64 {
+	need := headerSize + len(k) + len(v)
+
+	last := len(b.chunks) - 1
+	if last < 0 || cap(b.chunks[last])-len(b.chunks[last]) < need {
+		size := b.chunkSize
+		if need > size {
+			size = need
+		}
+		b.chunks = append(b.chunks, make([]byte, 0, size))
+		b.size += size
+		last++
+	}
+
+	chunk := b.chunks[last]
+	off := len(chunk)
+
+	var header [headerSize]byte
+	binary.LittleEndian.PutUint64(header[0:], hash)
+	binary.LittleEndian.PutUint32(header[8:], uint32(len(k)))
+	binary.LittleEndian.PutUint32(header[12:], uint32(len(v)))
+	chunk = append(chunk, header[:]...)
+	chunk = append(chunk, k...)
+	chunk = append(chunk, v...)
+	b.chunks[last] = chunk
+
+	return (b.first+uint64(last))<<32 | uint64(off)
+}
+
+// entry returns the serialised entry at the location.
+func (b *ByteMap) entry(loc uint64) []byte {
+	e := b.chunks[loc>>32-b.first][loc&(1<<32-1):]
+	return e[:entrySize(e)]
+}
+
+func entrySize(e []byte) int {
+	return headerSize + int(binary.LittleEndian.Uint32(e[8:])) + int(binary.LittleEndian.Uint32(e[12:]))
+}
+
+func split(e []byte) ([]byte, []byte) {
+	klen := int(binary.LittleEndian.Uint32(e[8:]))
+	return e[headerSize : headerSize+klen], e[headerSize+klen:]
+}
@@ -0,0 +1,206 @@
+package hashmap
+
+import (
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+func TestByteMap(t *testing.T) {
+	tests := map[string]struct {
+		adds    []entry
+		deletes []string
+		lookups []entry
+	}{
+		"SingleValue": {
+			[]entry{{key: "hello", value: "world"}},
+			nil,
+			[]entry{{key: "hello", value: "world"}},
+		},
+		"OverwriteValue": {
+			[]entry{{key: "hello", value: "world"}, {key: "hello", value: "foo"}},
+			nil,
+			[]entry{{key: "hello", value: "foo"}},
+		},
+		"Delete": {
+			[]entry{{key: "hello", value: "world"}, {key: "foo", value: "bar"}},
+			[]string{"hello"},
+			[]entry{{key: "hello"}, {key: "foo", value: "bar"}},
+		},
+		"Redistribute": {
+			redistributionTuples,
+			nil,
+			redistributionTuples,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := NewXXByteMap(0)
+			m2 := NewRuntimeByteMap(0)
+			for _, tpl := range test.adds {
+				m.Set([]byte(tpl.key), []byte(tpl.value.(string)))
+				m2.Set([]byte(tpl.key), []byte(tpl.value.(string)))
+			}
+
+			for _, k := range test.deletes {
+				if !m.Delete([]byte(k)) || !m2.Delete([]byte(k)) {
+					t.Fatalf("%s: expected delete", k)
+				}
+			}
+
+			for _, tpl := range test.lookups {
+				for _, m := range []*ByteMap{m, m2} {
+					v, ok := m.Get([]byte(tpl.key))
+					if tpl.value == nil {
+						if ok {
+							t.Fatalf("%s: expected no value, got '%s'", tpl.key, v)
+						}
+						continue
+					}
+					if string(v) != tpl.value {
+						t.Fatalf("%s: expected '%v', got '%s'", tpl.key, tpl.value, v)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestByteMapCollision(t *testing.T) {
+	m := NewByteMap(func([]byte) uint64 { return 42 }, 0)
+	m.Set([]byte("hello"), []byte("world"))
+	m.Set([]byte("foo"), []byte("bar"))
+
+	if _, ok := m.Get([]byte("hello")); ok {
+		t.Fatal("expected colliding key to be replaced")
+	}
+	if m.Delete([]byte("hello")) {
+		t.Fatal("expected colliding key not to be deleted")
+	}
+	if v, _ := m.Get([]byte("foo")); string(v) != "bar" {
+		t.Fatalf("expected 'bar', got '%s'", v)
+	}
+}
+
+func TestByteMapCompact(t *testing.T) {
+	m := newByteMapWithChunkSize(memHash, 0, 64)
+	for i := 0; i < 100; i++ {
+		m.Set([]byte(strconv.Itoa(i)), []byte("value"))
+	}
+	for i := 0; i < 100; i += 2 {
+		m.Delete([]byte(strconv.Itoa(i)))
+	}
+
+	before := m.Size()
+	m.Compact()
+	if m.Size() >= before {
+		t.Fatalf("expected size to shrink from %d, got %d", before, m.Size())
+	}
+	if m.Len() != 50 {
+		t.Fatalf("expected 50, got %d", m.Len())
+	}
+
+	for i := 0; i < 100; i++ {
+		v, ok := m.Get([]byte(strconv.Itoa(i)))
+		if ok != (i%2 == 1) {
+			t.Fatalf("%d: unexpected presence %v", i, ok)
+		}
+		if ok && string(v) != "value" {
+			t.Fatalf("%d: expected 'value', got '%s'", i, v)
+		}
+	}
+}
+
+func TestByteMapEvict(t *testing.T) {
+	m := newByteMapWithChunkSize(memHash, 256, 64)
+	for i := 0; i < 100; i++ {
+		m.Set([]byte(strconv.Itoa(i)), []byte("value"))
+	}
+
+	if m.Size() > 256 {
+		t.Fatalf("expected at most 256 bytes, got %d", m.Size())
+	}
+
+	// The newest keys survive and the oldest are evicted.
+	if _, ok := m.Get([]byte("99")); !ok {
+		t.Fatal("expected newest key")
+	}
+	if _, ok := m.Get([]byte("0")); ok {
+		t.Fatal("expected oldest key to be evicted")
+	}
+	if m.Len() == 0 || m.Len() == 100 {
+		t.Fatalf("unexpected length %d", m.Len())
+	}
+}
+
+const gcEntries = 1 << 18
+
+// benchmarkGC reports the time taken by a full collection
+// while the map built by fill is live.
+func benchmarkGC(b *testing.B, fill func() interface{}) {
+	m := fill()
+
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		runtime.GC()
+	}
+	b.StopTimer()
+
+	runtime.ReadMemStats(&after)
+	b.ReportMetric(float64(after.PauseTotalNs-before.PauseTotalNs)/float64(b.N), "pause-ns/op")
+	runtime.KeepAlive(m)
+}
+
+func BenchmarkGCByteMap(b *testing.B) {
+	benchmarkGC(b, func() interface{} {
+		m := NewXXByteMap(0)
+		for i := 0; i < gcEntries; i++ {
+			k := []byte(strconv.Itoa(i))
+			m.Set(k, k)
+		}
+		return m
+	})
+}
+
+func BenchmarkGCHashmap(b *testing.B) {
+	benchmarkGC(b, func() interface{} {
+		m := NewXXHashmap()
+		for i := 0; i < gcEntries; i++ {
+			k := strconv.Itoa(i)
+			m.Add(k, []byte(k))
+		}
+		return m
+	})
+}
+
+func BenchmarkGCGoHashmap(b *testing.B) {
+	benchmarkGC(b, func() interface{} {
+		m := make(map[string][]byte)
+		for i := 0; i < gcEntries; i++ {
+			k := strconv.Itoa(i)
+			m[k] = []byte(k)
+		}
+		return m
+	})
+}
+
+func BenchmarkByteMap(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		m := NewXXByteMap(0)
+		for _, tpl := range redistributionTuples {
+			m.Set([]byte(tpl.key), []byte(tpl.value.(string)))
+		}
+
+		for _, tpl := range redistributionTuples {
+			v, _ := m.Get([]byte(tpl.key))
+			_ = v
+		}
+	}
+}
@@ -8,7 +8,6 @@ package hashmap
 import (
 	"fmt"
 	"strings"
-	"sync/atomic"
 
 	"github.com/cespare/xxhash"
 	"github.com/segmentio/fasthash/fnv1a"
@@ -33,8 +32,9 @@ type Hashmap struct {
 	length int
 	fn     func(string) uint64
 
-	lock    uintptr
-	buckets [][8]entry
+	strategy LockStrategy
+	lock     locker
+	buckets  [][8]entry
 }
 
 // NewFNV1aHashmap returns a hashmap using the fnv1a
@@ -49,18 +49,26 @@ func NewXXHashmap() *Hashmap {
 	return NewHashmap(xxhash.Sum64String)
 }
 
-// NewHashmap creates a new, empty, hashmap.
+// NewHashmap creates a new, empty, hashmap guarded by a spin lock.
 func NewHashmap(fn func(string) uint64) *Hashmap {
-	return newWithCap(fn, 1<<length)
+	return NewHashmapWithLock(fn, SpinLock)
 }
 
-func newWithCap(fn func(string) uint64, cap int) *Hashmap {
+// NewHashmapWithLock creates a new, empty, hashmap guarded
+// by the specified lock strategy.
+func NewHashmapWithLock(fn func(string) uint64, s LockStrategy) *Hashmap {
+	return newWithCap(fn, 1<<length, s)
+}
+
+func newWithCap(fn func(string) uint64, cap int, s LockStrategy) *Hashmap {
 	h := &Hashmap{
-		lbound:  int(float64(int(1)<<length) * (1 - loadFactor)),
-		ubound:  int(float64(int(1)<<length) * loadFactor),
-		length:  cap,
-		buckets: make([][8]entry, cap),
-		fn:      fn,
+		lbound:   int(float64(int(1)<<length) * (1 - loadFactor)),
+		ubound:   int(float64(int(1)<<length) * loadFactor),
+		length:   cap,
+		buckets:  make([][8]entry, cap),
+		fn:       fn,
+		strategy: s,
+		lock:     newLocker(s),
 	}
 
 	if h.lbound == 1<<length || h.ubound == 1<<length {
@@ -73,11 +81,7 @@ func newWithCap(fn func(string) uint64, cap int) *Hashmap {
 // Add inserts the value v associated with the key k into the hashmap.
 // Redistribution of keys occurs if load factor is surpassed.
 func (h *Hashmap) Add(k string, v interface{}) bool {
-	for {
-		if atomic.CompareAndSwapUintptr(&h.lock, 0, 1) {
-			break
-		}
-	}
+	h.lock.Lock()
 
 	hash := h.fn(k)
 	idx := hash & (uint64(h.length) - 1)
@@ -117,18 +121,14 @@ func (h *Hashmap) Add(k string, v interface{}) bool {
 		h.resize()
 	}
 
-	atomic.StoreUintptr(&h.lock, 0)
+	h.lock.Unlock()
 	return !exists
 }
 
 // Delete removes the key from the map, if it exists,
 // and returns whether or not it was deleted.
 func (h *Hashmap) Delete(k string) bool {
-	for {
-		if atomic.CompareAndSwapUintptr(&h.lock, 0, 1) {
-			break
-		}
-	}
+	h.lock.Lock()
 
 	hash := h.fn(k)
 	idx := hash & (uint64(h.length) - 1)
@@ -155,30 +155,26 @@ func (h *Hashmap) Delete(k string) bool {
 		h.resize()
 	}
 
-	atomic.StoreUintptr(&h.lock, 0)
+	h.lock.Unlock()
 	return exists
 }
 
 // Lookup will try to retrieve the value associated with
 // the specified key.
 func (h *Hashmap) Lookup(k string) (interface{}, bool) {
-	for {
-		if atomic.CompareAndSwapUintptr(&h.lock, 0, 1) {
-			break
-		}
-	}
+	h.lock.RLock()
 
 	hash := h.fn(k)
 	idx := hash & (uint64(h.length) - 1)
 
 	for i := range h.buckets[idx] {
 		if h.buckets[idx][i].hash == hash {
-			atomic.StoreUintptr(&h.lock, 0)
+			h.lock.RUnlock()
 			return h.buckets[idx][i].value, true
 		}
 	}
 
-	atomic.StoreUintptr(&h.lock, 0)
+	h.lock.RUnlock()
 	return nil, false
 }
 
@@ -188,62 +184,67 @@ func (h *Hashmap) Iter(fn func(k string, v interface{}) bool) {
 		return
 	}
 
-	for {
-		if atomic.CompareAndSwapUintptr(&h.lock, 0, 1) {
-			break
-		}
-	}
+	h.lock.RLock()
 
 	for i := range h.buckets {
 		for j := range h.buckets[i] {
 			if !fn(h.buckets[i][j].key, h.buckets[i][j].value) {
-				atomic.StoreUintptr(&h.lock, 0)
+				h.lock.RUnlock()
 				return
 			}
 		}
 	}
 
-	atomic.StoreUintptr(&h.lock, 0)
+	h.lock.RUnlock()
 }
 
 // Len returns the number of elements in the map.
 func (h *Hashmap) Len() int {
-	for {
-		if atomic.CompareAndSwapUintptr(&h.lock, 0, 1) {
-			break
-		}
-	}
+	h.lock.RLock()
 
-	var length int
-	for i := range h.buckets {
-		for j := range h.buckets[i] {
-			if h.buckets[i][j].hash > 0 {
-				length++
-			}
-		}
-	}
+	length := h.count()
 
-	atomic.StoreUintptr(&h.lock, 0)
+	h.lock.RUnlock()
 	return length
 }
 
+// resize redistributes the entries into h.length buckets. Entries from
+// several buckets can land in the same one when shrinking, so the length
+// is doubled again until every entry fits.
 func (h *Hashmap) resize() {
-	buckets := make([][8]entry, h.length)
+	if h.length < 1 {
+		h.length = 1
+	}
 
-	for i := range h.buckets {
-		var length int
-		for j := range h.buckets[i] {
-			if h.buckets[i][j].hash == 0 {
+	for {
+		if buckets, ok := redistribute(h.buckets, h.length); ok {
+			h.buckets = buckets
+			return
+		}
+		h.length *= 2
+	}
+}
+
+func redistribute(old [][8]entry, length int) ([][8]entry, bool) {
+	buckets := make([][8]entry, length)
+	lengths := make([]int, length)
+
+	for i := range old {
+		for j := range old[i] {
+			if old[i][j].hash == 0 {
 				continue
 			}
-			idx := h.buckets[i][j].hash & (uint64(h.length) - 1)
+			idx := old[i][j].hash & (uint64(length) - 1)
+			if lengths[idx] == len(buckets[idx]) {
+				return nil, false
+			}
 
-			buckets[idx][length] = h.buckets[i][j]
-			length++
+			buckets[idx][lengths[idx]] = old[i][j]
+			lengths[idx]++
 		}
 	}
 
-	h.buckets = buckets
+	return buckets, true
 }
 
 func spew(buckets [][8]entry) string {
@@ -4,6 +4,8 @@ import (
 	"math/rand"
 	"sync"
 	"testing"
+
+	"github.com/iainanderson83/datastructures/internal/wordlist"
 )
 
 var (
@@ -17,12 +19,12 @@ func TestMain(m *testing.M) {
 
 	var i int
 	for {
-		key := wordList[rand.Intn(len(wordList))]
+		key := wordlist.Words[rand.Intn(len(wordlist.Words))]
 		if _, ok := ma[key]; ok {
 			continue
 		}
 		ma[key] = struct{}{}
-		tpl := entry{key: key, value: wordList[rand.Intn(len(wordList))]}
+		tpl := entry{key: key, value: wordlist.Words[rand.Intn(len(wordlist.Words))]}
 		redistributionTuples = append(redistributionTuples, tpl)
 
 		i++
@@ -0,0 +1,108 @@
+package hashmap
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// LockStrategy selects the lock used to guard a map.
+type LockStrategy int
+
+const (
+	// SpinLock spins on a CAS until the lock is acquired. It never yields,
+	// so it is only suitable for short critical sections with few goroutines.
+	SpinLock LockStrategy = iota
+	// BackoffSpinLock spins on a CAS, yielding the processor an
+	// exponentially increasing number of times between attempts.
+	BackoffSpinLock
+	// Mutex uses a sync.Mutex, which readers also take exclusively.
+	Mutex
+	// RWMutex uses a sync.RWMutex, so readers do not block each other.
+	RWMutex
+	// NoLock does no locking, for maps used by a single goroutine.
+	NoLock
+)
+
+// maxBackoff is the maximum number of yields between attempts.
+const maxBackoff = 64
+
+var (
+	_ locker = &spinLock{}
+	_ locker = &backoffSpinLock{}
+	_ locker = &mutex{}
+	_ locker = &sync.RWMutex{}
+	_ locker = noLock{}
+)
+
+// locker guards a map. Read only operations take the read lock,
+// which implementations without shared locking treat as exclusive.
+type locker interface {
+	Lock()
+	Unlock()
+	RLock()
+	RUnlock()
+}
+
+func newLocker(s LockStrategy) locker {
+	switch s {
+	case SpinLock:
+		return &spinLock{}
+	case BackoffSpinLock:
+		return &backoffSpinLock{}
+	case Mutex:
+		return &mutex{}
+	case RWMutex:
+		return &sync.RWMutex{}
+	case NoLock:
+		return noLock{}
+	}
+	panic("invalid lock strategy")
+}
+
+type spinLock struct {
+	state uintptr
+}
+
+func (s *spinLock) Lock() {
+	for !atomic.CompareAndSwapUintptr(&s.state, 0, 1) {
+	}
+}
+
+func (s *spinLock) Unlock()  { atomic.StoreUintptr(&s.state, 0) }
+func (s *spinLock) RLock()   { s.Lock() }
+func (s *spinLock) RUnlock() { s.Unlock() }
+
+type backoffSpinLock struct {
+	state uintptr
+}
+
+func (s *backoffSpinLock) Lock() {
+	backoff := 1
+	for !atomic.CompareAndSwapUintptr(&s.state, 0, 1) {
+		for i := 0; i < backoff; i++ {
+			runtime.Gosched()
+		}
+		if backoff < maxBackoff {
+			backoff <<= 1
+		}
+	}
+}
+
+func (s *backoffSpinLock) Unlock()  { atomic.StoreUintptr(&s.state, 0) }
+func (s *backoffSpinLock) RLock()   { s.Lock() }
+func (s *backoffSpinLock) RUnlock() { s.Unlock() }
+
+type mutex struct {
+	sync.Mutex
+}
+
+func (m *mutex) RLock()   { m.Lock() }
+func (m *mutex) RUnlock() { m.Unlock() }
+
+type noLock struct{}
+
+func (noLock) Lock()    {}
+func (noLock) Unlock()  {}
+func (noLock) RLock()   {}
+func (noLock) RUnlock() {}
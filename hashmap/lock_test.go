@@ -0,0 +1,137 @@
+package hashmap
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/cespare/xxhash"
+)
+
+var lockStrategies = map[string]LockStrategy{
+	"SpinLock":        SpinLock,
+	"BackoffSpinLock": BackoffSpinLock,
+	"Mutex":           Mutex,
+	"RWMutex":         RWMutex,
+	"NoLock":          NoLock,
+}
+
+func TestLockStrategies(t *testing.T) {
+	for name, s := range lockStrategies {
+		t.Run(name, func(t *testing.T) {
+			workers := 8
+			if s == NoLock {
+				workers = 1
+			}
+
+			m := NewHashmapWithLock(xxhash.Sum64String, s)
+			o := NewOrderedMapWithLock(xxhash.Sum64String, s)
+
+			var wg sync.WaitGroup
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func(w int) {
+					defer wg.Done()
+					for i := 0; i < 100; i++ {
+						k := strconv.Itoa(w*100 + i)
+						m.Add(k, i)
+						o.Add(k, i)
+						m.Lookup(k)
+						o.Lookup(k)
+						m.Len()
+					}
+				}(w)
+			}
+			wg.Wait()
+
+			if m.Len() != workers*100 || o.Len() != workers*100 {
+				t.Fatalf("expected %d, got %d and %d", workers*100, m.Len(), o.Len())
+			}
+			if c := m.Clone(); c.Len() != m.Len() || c.strategy != s {
+				t.Fatal("clone does not match")
+			}
+		})
+	}
+}
+
+func TestInvalidLockStrategy(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	NewHashmapWithLock(xxhash.Sum64String, LockStrategy(-1))
+}
+
+// benchmarkContention runs a read heavy workload, one write in ten,
+// across GOMAXPROCS values with parallelism goroutines per proc.
+func benchmarkContention(b *testing.B, parallelism int) {
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+
+	for _, procs := range []int{1, 2, 4, 8} {
+		for name, s := range lockStrategies {
+			if s == NoLock {
+				continue
+			}
+
+			b.Run(fmt.Sprintf("%s/procs=%d", name, procs), func(b *testing.B) {
+				defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+
+				m := NewHashmapWithLock(xxhash.Sum64String, s)
+				for _, k := range keys {
+					m.Add(k, k)
+				}
+
+				b.SetParallelism(parallelism)
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					var i int
+					for pb.Next() {
+						k := keys[i%len(keys)]
+						if i%10 == 0 {
+							m.Add(k, k)
+						} else {
+							m.Lookup(k)
+						}
+						i++
+					}
+				})
+			})
+		}
+	}
+}
+
+func BenchmarkContention(b *testing.B) {
+	benchmarkContention(b, 1)
+}
+
+// BenchmarkOversubscribed runs more goroutines than procs,
+// where locks that never yield starve the lock holder.
+func BenchmarkOversubscribed(b *testing.B) {
+	benchmarkContention(b, 8)
+}
+
+func BenchmarkUncontended(b *testing.B) {
+	for name, s := range lockStrategies {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				m := NewHashmapWithLock(xxhash.Sum64String, s)
+				for _, tpl := range redistributionTuples {
+					m.Add(tpl.key, tpl.value)
+				}
+
+				for _, tpl := range redistributionTuples {
+					v, _ := m.Lookup(tpl.key)
+					_ = v
+				}
+			}
+		})
+	}
+}
@@ -1,27 +1,28 @@
 package hashmap
 
-import "sync/atomic"
-
 // OrderedMap is an ordered variant of Hashmap.
 type OrderedMap struct {
-	lock uintptr
+	lock locker
 	i    []string
 	m    *Hashmap
 }
 
 // NewOrderedMap creates a new ordered map with the specified hashing function.
 func NewOrderedMap(fn func(string) uint64) *OrderedMap {
-	return &OrderedMap{m: NewHashmap(fn)}
+	return NewOrderedMapWithLock(fn, SpinLock)
+}
+
+// NewOrderedMapWithLock creates a new ordered map with the specified
+// hashing function, guarded by the specified lock strategy.
+func NewOrderedMapWithLock(fn func(string) uint64, s LockStrategy) *OrderedMap {
+	// The inner map is only accessed under the ordered map's lock.
+	return &OrderedMap{lock: newLocker(s), m: NewHashmapWithLock(fn, NoLock)}
 }
 
 // Iter calls the specified cb for each key/value pair in the map
 // in the inserted order.
 func (o *OrderedMap) Iter(fn func(k string, v interface{}) bool) {
-	for {
-		if atomic.CompareAndSwapUintptr(&o.lock, 0, 1) {
-			break
-		}
-	}
+	o.lock.Lock()
 
 	for i := range o.i {
 		v, b := o.m.Lookup(o.i[i])
@@ -31,35 +32,27 @@ func (o *OrderedMap) Iter(fn func(k string, v interface{}) bool) {
 		}
 
 		if !fn(o.i[i], v) {
-			atomic.StoreUintptr(&o.lock, 0)
+			o.lock.Unlock()
 			return
 		}
 	}
 
-	atomic.StoreUintptr(&o.lock, 0)
+	o.lock.Unlock()
 }
 
 // Lookup returns the value associated with the specified key in the map.
 func (o *OrderedMap) Lookup(k string) (interface{}, bool) {
-	for {
-		if atomic.CompareAndSwapUintptr(&o.lock, 0, 1) {
-			break
-		}
-	}
+	o.lock.RLock()
 
 	v, b := o.m.Lookup(k)
 
-	atomic.StoreUintptr(&o.lock, 0)
+	o.lock.RUnlock()
 	return v, b
 }
 
 // Delete removes the value associated with the specified key from the map.
 func (o *OrderedMap) Delete(k string) bool {
-	for {
-		if atomic.CompareAndSwapUintptr(&o.lock, 0, 1) {
-			break
-		}
-	}
+	o.lock.Lock()
 
 	deleted := o.m.Delete(k)
 	if deleted {
@@ -76,37 +69,29 @@ func (o *OrderedMap) Delete(k string) bool {
 		}
 	}
 
-	atomic.StoreUintptr(&o.lock, 0)
+	o.lock.Unlock()
 	return deleted
 }
 
 // Add adds the specified value to the map with the specified key.
 func (o *OrderedMap) Add(k string, v interface{}) bool {
-	for {
-		if atomic.CompareAndSwapUintptr(&o.lock, 0, 1) {
-			break
-		}
-	}
+	o.lock.Lock()
 
 	added := o.m.Add(k, v)
 	if added {
 		o.i = append(o.i, k)
 	}
 
-	atomic.StoreUintptr(&o.lock, 0)
+	o.lock.Unlock()
 	return added
 }
 
 // Len returns the number of elements in  the map.
 func (o *OrderedMap) Len() int {
-	for {
-		if atomic.CompareAndSwapUintptr(&o.lock, 0, 1) {
-			break
-		}
-	}
+	o.lock.RLock()
 
 	length := len(o.i)
 
-	atomic.StoreUintptr(&o.lock, 0)
+	o.lock.RUnlock()
 	return length
 }
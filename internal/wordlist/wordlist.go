@@ -1,6 +1,7 @@
-package hashmap
+package wordlist
 
-var wordList = []string{
+// Words is a list of common English words.
+var Words = []string{
 	"a",
 	"ability",
 	"able",
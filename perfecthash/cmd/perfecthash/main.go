@@ -0,0 +1,83 @@
+// Command perfecthash generates Go source for a perfecthash.StaticMap.
+//
+// The input has one key per line, optionally followed by a tab and
+// a value. It is intended to be used with go:generate, for example:
+//
+//	//go:generate go run github.com/iainanderson83/datastructures/perfecthash/cmd/perfecthash -in words.txt -out words.go -pkg words -var Words
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/iainanderson83/datastructures/perfecthash"
+)
+
+func main() {
+	var (
+		in   = flag.String("in", "", "input file, defaults to stdin")
+		out  = flag.String("out", "", "output file, defaults to stdout")
+		pkg  = flag.String("pkg", "main", "package name of the generated file")
+		name = flag.String("var", "Map", "variable name of the generated map")
+	)
+	flag.Parse()
+
+	if err := run(*in, *out, *pkg, *name); err != nil {
+		fmt.Fprintln(os.Stderr, "perfecthash:", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out, pkg, name string) error {
+	r := io.Reader(os.Stdin)
+	if in != "" {
+		f, err := os.Open(in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var (
+		keys, values []string
+		hasValues    bool
+	)
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		if line == "" {
+			continue
+		}
+
+		k, v := line, ""
+		if i := strings.IndexByte(line, '\t'); i >= 0 {
+			k, v = line[:i], line[i+1:]
+			hasValues = true
+		}
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+	if !hasValues {
+		values = nil
+	}
+
+	var b bytes.Buffer
+	if err := perfecthash.Generate(&b, pkg, name, keys, values); err != nil {
+		return err
+	}
+
+	if out == "" {
+		_, err := os.Stdout.Write(b.Bytes())
+		return err
+	}
+	return os.WriteFile(out, b.Bytes(), 0644)
+}
@@ -0,0 +1,74 @@
+package perfecthash
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+)
+
+// Generate writes Go source declaring a variable called name, in the
+// package pkg, which holds a StaticMap of the keys to the values.
+// The values may be nil, in which case the map is a set.
+func Generate(w io.Writer, pkg, name string, keys, values []string) error {
+	if values != nil && len(values) != len(keys) {
+		return errors.New("mismatched keys and values")
+	}
+
+	t, err := Build(keys)
+	if err != nil {
+		return err
+	}
+
+	ordered := make([]string, len(keys))
+	var orderedValues []string
+	if values != nil {
+		orderedValues = make([]string, len(values))
+	}
+	for i := range keys {
+		idx := t.Index(keys[i])
+		ordered[idx] = keys[i]
+		if values != nil {
+			orderedValues[idx] = values[i]
+		}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by perfecthash. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "import \"github.com/iainanderson83/datastructures/perfecthash\"\n\n")
+	fmt.Fprintf(&b, "var %s = perfecthash.MustLoad(\n", name)
+
+	b.WriteString("[]int32{")
+	for i, s := range t.seeds {
+		if i%16 == 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%d, ", s)
+	}
+	b.WriteString("\n},\n[]string{\n")
+	for _, k := range ordered {
+		fmt.Fprintf(&b, "%q,\n", k)
+	}
+	b.WriteString("},\n")
+
+	if values == nil {
+		b.WriteString("nil,\n")
+	} else {
+		b.WriteString("[]interface{}{\n")
+		for _, v := range orderedValues {
+			fmt.Fprintf(&b, "%q,\n", v)
+		}
+		b.WriteString("},\n")
+	}
+	b.WriteString(")\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(src)
+	return err
+}
@@ -0,0 +1,185 @@
+package perfecthash
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"github.com/cespare/xxhash"
+)
+
+const (
+	// average number of keys per bucket
+	bucketSize = 2
+
+	maxSeed = 1 << 24
+)
+
+var (
+	errDuplicate = errors.New("duplicate key or hash collision")
+	errNoSeed    = errors.New("no seed found for bucket")
+	errCorrupt   = errors.New("corrupt table")
+)
+
+// Table is a minimal perfect hash function for a fixed set of keys,
+// built with hash and displace. The keys are split into buckets and
+// each bucket stores the seed that places its keys in free slots.
+type Table struct {
+	n     int
+	seeds []int32 // < 0 places a single key directly in slot -seed-1
+}
+
+// Build creates a table mapping each of the keys to a unique index in [0, len(keys)).
+func Build(keys []string) (*Table, error) {
+	t := &Table{n: len(keys), seeds: make([]int32, len(keys)/bucketSize+1)}
+	if len(keys) == 0 {
+		return t, nil
+	}
+
+	hashes := make([]uint64, len(keys))
+	buckets := make([][]int, len(t.seeds))
+	for i := range keys {
+		hashes[i] = xxhash.Sum64String(keys[i])
+		b := hashes[i] % uint64(len(buckets))
+		buckets[b] = append(buckets[b], i)
+	}
+
+	order := make([]int, len(buckets))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(buckets[order[i]]) > len(buckets[order[j]])
+	})
+
+	used := make([]bool, t.n)
+	slots := make([]int, 0, bucketSize)
+
+	var next int
+	for _, b := range order {
+		switch len(buckets[b]) {
+		case 0:
+			continue
+		case 1:
+			// Place single keys in the remaining slots, no seed required.
+			for used[next] {
+				next++
+			}
+			used[next] = true
+			t.seeds[b] = int32(-next - 1)
+			continue
+		}
+
+		// Keys with the same hash can never be separated.
+		for i, k := range buckets[b] {
+			for _, o := range buckets[b][:i] {
+				if hashes[k] == hashes[o] {
+					return nil, errDuplicate
+				}
+			}
+		}
+
+	search:
+		for seed := 0; ; seed++ {
+			if seed == maxSeed {
+				return nil, errNoSeed
+			}
+
+			slots = slots[:0]
+			for _, k := range buckets[b] {
+				slot := t.slot(hashes[k], int32(seed))
+				if used[slot] {
+					continue search
+				}
+				for _, s := range slots {
+					if s == slot {
+						continue search
+					}
+				}
+				slots = append(slots, slot)
+			}
+
+			for _, s := range slots {
+				used[s] = true
+			}
+			t.seeds[b] = int32(seed)
+			break
+		}
+	}
+
+	return t, nil
+}
+
+// Len returns the number of keys the table was built with.
+func (t *Table) Len() int {
+	return t.n
+}
+
+// Index returns the index for the key. Keys which were not
+// used to build the table return an arbitrary index.
+func (t *Table) Index(k string) int {
+	if t.n == 0 {
+		return 0
+	}
+
+	hash := xxhash.Sum64String(k)
+	seed := t.seeds[hash%uint64(len(t.seeds))]
+	if seed < 0 {
+		return int(-seed - 1)
+	}
+	return t.slot(hash, seed)
+}
+
+func (t *Table) slot(hash uint64, seed int32) int {
+	return int(mix(hash^uint64(seed)) % uint64(t.n))
+}
+
+// MarshalBinary encodes the table.
+func (t *Table) MarshalBinary() ([]byte, error) {
+	out := make([]byte, (len(t.seeds)+2)*binary.MaxVarintLen64)
+	off := binary.PutUvarint(out, uint64(t.n))
+	off += binary.PutUvarint(out[off:], uint64(len(t.seeds)))
+	for _, s := range t.seeds {
+		off += binary.PutVarint(out[off:], int64(s))
+	}
+	return out[:off], nil
+}
+
+// UnmarshalBinary decodes a table encoded by MarshalBinary.
+func (t *Table) UnmarshalBinary(data []byte) error {
+	n, read := binary.Uvarint(data)
+	if read <= 0 {
+		return errCorrupt
+	}
+	data = data[read:]
+
+	count, read := binary.Uvarint(data)
+	if read <= 0 || count > uint64(len(data)) {
+		return errCorrupt
+	}
+	data = data[read:]
+
+	seeds := make([]int32, count)
+	for i := range seeds {
+		s, read := binary.Varint(data)
+		if read <= 0 || s < -int64(n) || s >= maxSeed {
+			return errCorrupt
+		}
+		seeds[i] = int32(s)
+		data = data[read:]
+	}
+	if len(data) != 0 || len(seeds) == 0 {
+		return errCorrupt
+	}
+
+	t.n, t.seeds = int(n), seeds
+	return nil
+}
+
+// mix is the splitmix64 finalizer.
+func mix(h uint64) uint64 {
+	h += 0x9e3779b97f4a7c15
+	h = (h ^ (h >> 30)) * 0xbf58476d1ce4e5b9
+	h = (h ^ (h >> 27)) * 0x94d049bb133111eb
+	return h ^ (h >> 31)
+}
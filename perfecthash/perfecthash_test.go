@@ -0,0 +1,251 @@
+package perfecthash
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/iainanderson83/datastructures/hashmap"
+	"github.com/iainanderson83/datastructures/internal/wordlist"
+)
+
+func TestBuild(t *testing.T) {
+	tests := map[string][]string{
+		"Empty":    nil,
+		"Single":   {"hello"},
+		"Small":    {"hello", "world", "foo", "bar", "baz"},
+		"WordList": wordlist.Words,
+	}
+
+	for name, keys := range tests {
+		t.Run(name, func(t *testing.T) {
+			tbl, err := Build(keys)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			seen := make([]bool, len(keys))
+			for _, k := range keys {
+				idx := tbl.Index(k)
+				if idx < 0 || idx >= len(keys) {
+					t.Fatalf("%s: index %d out of range", k, idx)
+				}
+				if seen[idx] {
+					t.Fatalf("%s: index %d collides", k, idx)
+				}
+				seen[idx] = true
+			}
+		})
+	}
+}
+
+func TestDuplicate(t *testing.T) {
+	if _, err := Build([]string{"hello", "world", "hello"}); err != errDuplicate {
+		t.Fatalf("expected %v, got %v", errDuplicate, err)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	tbl, err := Build(wordlist.Words)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := tbl.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out Table
+	if err := out.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range wordlist.Words {
+		if tbl.Index(k) != out.Index(k) {
+			t.Fatalf("%s: expected %d, got %d", k, tbl.Index(k), out.Index(k))
+		}
+	}
+
+	for _, bad := range [][]byte{nil, data[:len(data)-1], append(data, 0)} {
+		if err := out.UnmarshalBinary(bad); err == nil {
+			t.Fatal("expected error for corrupt table")
+		}
+	}
+}
+
+func TestStaticMap(t *testing.T) {
+	values := make([]interface{}, len(wordlist.Words))
+	for i := range values {
+		values[i] = i
+	}
+
+	m, err := NewStaticMap(wordlist.Words, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != len(wordlist.Words) {
+		t.Fatalf("expected %d, got %d", len(wordlist.Words), m.Len())
+	}
+
+	for i, k := range wordlist.Words {
+		v, ok := m.Lookup(k)
+		if !ok || v != i {
+			t.Fatalf("%s: expected %d, got %v", k, i, v)
+		}
+	}
+	for _, k := range []string{"", "hello world", "zzz", "abilityx"} {
+		if _, ok := m.Lookup(k); ok {
+			t.Fatalf("%s: expected no value", k)
+		}
+	}
+
+	var count int
+	m.Iter(func(k string, v interface{}) bool {
+		if wordlist.Words[v.(int)] != k {
+			t.Fatalf("%s: unexpected value %v", k, v)
+		}
+		count++
+		return true
+	})
+	if count != len(wordlist.Words) {
+		t.Fatalf("expected %d, got %d", len(wordlist.Words), count)
+	}
+
+	// Reloading the keys in index order gives the same map.
+	var keys []string
+	m.Iter(func(k string, v interface{}) bool {
+		keys = append(keys, k)
+		return true
+	})
+	if _, err := Load(m.Table(), keys, nil); err != nil {
+		t.Fatal(err)
+	}
+	keys[0], keys[1] = keys[1], keys[0]
+	if _, err := Load(m.Table(), keys, nil); err == nil {
+		t.Fatal("expected error for keys out of place")
+	}
+}
+
+func TestStaticSet(t *testing.T) {
+	m, err := NewStaticMap([]string{"hello", "world"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := m.Lookup("hello"); !ok || v != nil {
+		t.Fatalf("expected nil, got %v", v)
+	}
+	if _, ok := m.Lookup("foo"); ok {
+		t.Fatal("expected no value")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := map[string]struct {
+		keys   []string
+		values []string
+	}{
+		"Set": {
+			[]string{"hello", "world", "foo"},
+			nil,
+		},
+		"Map": {
+			[]string{"hello", "world", "foo"},
+			[]string{"1", "2", "3"},
+		},
+		"Quoted": {
+			[]string{"\"quoted\"", "tab\there", "new\nline"},
+			[]string{"`", "\\", ""},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := Generate(&b, "words", "Words", test.keys, test.values); err != nil {
+				t.Fatal(err)
+			}
+
+			src := b.String()
+			if _, err := parser.ParseFile(token.NewFileSet(), "words.go", src, 0); err != nil {
+				t.Fatalf("generated source does not parse: %v\n%s", err, src)
+			}
+			if !strings.Contains(src, "var Words = perfecthash.MustLoad(") {
+				t.Fatalf("unexpected source:\n%s", src)
+			}
+			for _, k := range test.keys {
+				if !strings.Contains(src, strconv.Quote(k)) {
+					t.Fatalf("%s: missing from source:\n%s", k, src)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkStaticMapLookup(b *testing.B) {
+	m, err := NewStaticMap(wordlist.Words, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, w := range wordlist.Words {
+			v, _ := m.Lookup(w)
+			_ = v
+		}
+	}
+}
+
+func BenchmarkHashmapLookup(b *testing.B) {
+	m := hashmap.NewXXHashmap()
+	for _, w := range wordlist.Words {
+		m.Add(w, nil)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, w := range wordlist.Words {
+			v, _ := m.Lookup(w)
+			_ = v
+		}
+	}
+}
+
+// benchmarkMemory reports the bytes retained by the value built by fn.
+func benchmarkMemory(b *testing.B, fn func() interface{}) {
+	var before, after runtime.MemStats
+	var retained int64
+	for i := 0; i < b.N; i++ {
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		v := fn()
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		retained += int64(after.HeapAlloc) - int64(before.HeapAlloc)
+		runtime.KeepAlive(v)
+	}
+	b.ReportMetric(float64(retained)/float64(b.N), "bytes/map")
+}
+
+func BenchmarkStaticMapMemory(b *testing.B) {
+	benchmarkMemory(b, func() interface{} {
+		m, _ := NewStaticMap(wordlist.Words, nil)
+		return m
+	})
+}
+
+func BenchmarkHashmapMemory(b *testing.B) {
+	benchmarkMemory(b, func() interface{} {
+		m := hashmap.NewXXHashmap()
+		for _, w := range wordlist.Words {
+			m.Add(w, nil)
+		}
+		return m
+	})
+}
@@ -0,0 +1,103 @@
+package perfecthash
+
+import (
+	"errors"
+	"fmt"
+)
+
+// StaticMap is a read-only map backed by a minimal perfect hash.
+// Lookups hash the key once and compare it against a single slot.
+type StaticMap struct {
+	table  *Table
+	keys   []string
+	values []interface{}
+}
+
+// NewStaticMap builds a map of the keys to the values. The values
+// may be nil, in which case the map is a set.
+func NewStaticMap(keys []string, values []interface{}) (*StaticMap, error) {
+	if values != nil && len(values) != len(keys) {
+		return nil, errors.New("mismatched keys and values")
+	}
+
+	t, err := Build(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &StaticMap{table: t, keys: make([]string, len(keys))}
+	if values != nil {
+		s.values = make([]interface{}, len(values))
+	}
+	for i := range keys {
+		idx := t.Index(keys[i])
+		s.keys[idx] = keys[i]
+		if values != nil {
+			s.values[idx] = values[i]
+		}
+	}
+	return s, nil
+}
+
+// Load creates a map from a table and the keys and values
+// in index order, as written by Generate.
+func Load(t *Table, keys []string, values []interface{}) (*StaticMap, error) {
+	if len(t.seeds) == 0 || len(keys) != t.Len() || (values != nil && len(values) != len(keys)) {
+		return nil, errors.New("mismatched table, keys and values")
+	}
+
+	for i := range keys {
+		if t.Index(keys[i]) != i {
+			return nil, fmt.Errorf("key %q is out of place", keys[i])
+		}
+	}
+	return &StaticMap{table: t, keys: keys, values: values}, nil
+}
+
+// MustLoad is like Load but panics on error. It is used by generated code.
+func MustLoad(seeds []int32, keys []string, values []interface{}) *StaticMap {
+	s, err := Load(&Table{n: len(keys), seeds: seeds}, keys, values)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Lookup returns the value associated with the specified key.
+func (s *StaticMap) Lookup(k string) (interface{}, bool) {
+	if len(s.keys) == 0 {
+		return nil, false
+	}
+
+	idx := s.table.Index(k)
+	if s.keys[idx] != k {
+		return nil, false
+	}
+	if s.values == nil {
+		return nil, true
+	}
+	return s.values[idx], true
+}
+
+// Iter calls the provided cb for each key/value pair in the map.
+func (s *StaticMap) Iter(fn func(k string, v interface{}) bool) {
+	for i := range s.keys {
+		var v interface{}
+		if s.values != nil {
+			v = s.values[i]
+		}
+		if !fn(s.keys[i], v) {
+			return
+		}
+	}
+}
+
+// Len returns the number of elements in the map.
+func (s *StaticMap) Len() int {
+	return len(s.keys)
+}
+
+// Table returns the perfect hash function used by the map.
+func (s *StaticMap) Table() *Table {
+	return s.table
+}
@@ -0,0 +1,277 @@
+package radix
+
+import (
+	"sort"
+	"strings"
+)
+
+// WalkFn is called for each key/value pair during a walk.
+// Returning false stops the walk.
+type WalkFn func(k string, v interface{}) bool
+
+type leaf struct {
+	key   string
+	value interface{}
+}
+
+type node struct {
+	// prefix is the label of the edge leading to this node.
+	prefix string
+	leaf   *leaf
+
+	// edges are kept sorted by the first byte of their prefix.
+	edges []*node
+}
+
+// Tree is a radix tree, a trie in which every node with a single
+// child is merged with that child.
+type Tree struct {
+	root *node
+	size int
+}
+
+// New creates a new, empty, tree.
+func New() *Tree {
+	return &Tree{root: &node{}}
+}
+
+// Len returns the number of keys in the tree.
+func (t *Tree) Len() int {
+	return t.size
+}
+
+// Insert adds the key and value to the tree, overwriting any
+// existing value, and returns whether or not the key was added.
+func (t *Tree) Insert(k string, v interface{}) bool {
+	n, search := t.root, k
+	for {
+		if len(search) == 0 {
+			if n.leaf != nil {
+				n.leaf.value = v
+				return false
+			}
+			n.leaf = &leaf{k, v}
+			t.size++
+			return true
+		}
+
+		idx, child := n.edge(search[0])
+		if child == nil {
+			n.addEdge(&node{prefix: search, leaf: &leaf{k, v}})
+			t.size++
+			return true
+		}
+
+		common := commonPrefix(search, child.prefix)
+		if common == len(child.prefix) {
+			n, search = child, search[common:]
+			continue
+		}
+
+		// Split the edge at the end of the common prefix.
+		split := &node{prefix: search[:common]}
+		n.edges[idx] = split
+		child.prefix = child.prefix[common:]
+		split.addEdge(child)
+
+		search = search[common:]
+		if len(search) == 0 {
+			split.leaf = &leaf{k, v}
+		} else {
+			split.addEdge(&node{prefix: search, leaf: &leaf{k, v}})
+		}
+		t.size++
+		return true
+	}
+}
+
+// Get returns the value associated with the key.
+func (t *Tree) Get(k string) (interface{}, bool) {
+	n, search := t.root, k
+	for len(search) > 0 {
+		_, child := n.edge(search[0])
+		if child == nil || !strings.HasPrefix(search, child.prefix) {
+			return nil, false
+		}
+		n, search = child, search[len(child.prefix):]
+	}
+
+	if n.leaf == nil {
+		return nil, false
+	}
+	return n.leaf.value, true
+}
+
+// Delete removes the key from the tree and returns whether or not it was deleted.
+func (t *Tree) Delete(k string) bool {
+	var (
+		parent *node
+		n      = t.root
+		search = k
+	)
+	for len(search) > 0 {
+		_, child := n.edge(search[0])
+		if child == nil || !strings.HasPrefix(search, child.prefix) {
+			return false
+		}
+		parent, n, search = n, child, search[len(child.prefix):]
+	}
+
+	if n.leaf == nil {
+		return false
+	}
+	n.leaf = nil
+	t.size--
+
+	if parent != nil && len(n.edges) == 0 {
+		parent.removeEdge(n.prefix[0])
+		n = parent
+	}
+
+	// Merge any node left with a single child and no value.
+	if n != t.root && n.leaf == nil && len(n.edges) == 1 {
+		n.merge()
+	}
+	return true
+}
+
+// LongestPrefix returns the longest key in the tree that is a prefix of s.
+func (t *Tree) LongestPrefix(s string) (string, interface{}, bool) {
+	var (
+		last   *leaf
+		n      = t.root
+		search = s
+	)
+	for {
+		if n.leaf != nil {
+			last = n.leaf
+		}
+		if len(search) == 0 {
+			break
+		}
+
+		_, child := n.edge(search[0])
+		if child == nil || !strings.HasPrefix(search, child.prefix) {
+			break
+		}
+		n, search = child, search[len(child.prefix):]
+	}
+
+	if last == nil {
+		return "", nil, false
+	}
+	return last.key, last.value, true
+}
+
+// Walk calls fn for each key/value pair in the tree in key order.
+func (t *Tree) Walk(fn WalkFn) {
+	walk(t.root, fn)
+}
+
+// WalkPrefix calls fn, in key order, for each key/value
+// pair whose key starts with prefix.
+func (t *Tree) WalkPrefix(prefix string, fn WalkFn) {
+	n, search := t.root, prefix
+	for len(search) > 0 {
+		_, child := n.edge(search[0])
+		if child == nil {
+			return
+		}
+
+		if strings.HasPrefix(search, child.prefix) {
+			n, search = child, search[len(child.prefix):]
+			continue
+		}
+
+		// The prefix ends part way along the edge.
+		if strings.HasPrefix(child.prefix, search) {
+			walk(child, fn)
+		}
+		return
+	}
+
+	walk(n, fn)
+}
+
+// WalkPath calls fn for each key/value pair whose key is a prefix
+// of path, from the shortest key to the longest.
+func (t *Tree) WalkPath(path string, fn WalkFn) {
+	n, search := t.root, path
+	for {
+		if n.leaf != nil && !fn(n.leaf.key, n.leaf.value) {
+			return
+		}
+		if len(search) == 0 {
+			return
+		}
+
+		_, child := n.edge(search[0])
+		if child == nil || !strings.HasPrefix(search, child.prefix) {
+			return
+		}
+		n, search = child, search[len(child.prefix):]
+	}
+}
+
+// walk visits the node and its children in key order and
+// returns false if the walk was stopped.
+func walk(n *node, fn WalkFn) bool {
+	if n.leaf != nil && !fn(n.leaf.key, n.leaf.value) {
+		return false
+	}
+
+	for _, e := range n.edges {
+		if !walk(e, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *node) edge(label byte) (int, *node) {
+	idx := sort.Search(len(n.edges), func(i int) bool {
+		return n.edges[i].prefix[0] >= label
+	})
+	if idx < len(n.edges) && n.edges[idx].prefix[0] == label {
+		return idx, n.edges[idx]
+	}
+	return idx, nil
+}
+
+func (n *node) addEdge(e *node) {
+	idx, _ := n.edge(e.prefix[0])
+	n.edges = append(n.edges, nil)
+	copy(n.edges[idx+1:], n.edges[idx:])
+	n.edges[idx] = e
+}
+
+func (n *node) removeEdge(label byte) {
+	idx, e := n.edge(label)
+	if e == nil {
+		return
+	}
+	copy(n.edges[idx:], n.edges[idx+1:])
+	n.edges[len(n.edges)-1] = nil // GC
+	n.edges = n.edges[:len(n.edges)-1]
+}
+
+// merge folds the only child of the node into it.
+func (n *node) merge() {
+	child := n.edges[0]
+	n.prefix += child.prefix
+	n.leaf = child.leaf
+	n.edges = child.edges
+}
+
+func commonPrefix(a, b string) int {
+	max := len(a)
+	if len(b) < max {
+		max = len(b)
+	}
+
+	var i int
+	for i < max && a[i] == b[i] {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,337 @@
+package radix
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/iainanderson83/datastructures/hashmap"
+	"github.com/iainanderson83/datastructures/internal/wordlist"
+)
+
+// isSameSlice returns true if the 2 slices are identical
+func isSameSlice(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// validate checks that every node other than the root holds a
+// value or branches, so that no node could have been merged.
+func validate(t *testing.T, n *node, root bool) int {
+	if !root && n.leaf == nil && len(n.edges) < 2 {
+		t.Fatalf("node %q should have been merged", n.prefix)
+	}
+
+	count := 0
+	if n.leaf != nil {
+		count++
+	}
+	for i, e := range n.edges {
+		if e.prefix == "" {
+			t.Fatal("empty edge")
+		}
+		if i > 0 && n.edges[i-1].prefix[0] >= e.prefix[0] {
+			t.Fatalf("edges out of order at %q", e.prefix)
+		}
+		count += validate(t, e, false)
+	}
+	return count
+}
+
+func keys(walk func(WalkFn)) []string {
+	var out []string
+	walk(func(k string, v interface{}) bool {
+		out = append(out, k)
+		return true
+	})
+	return out
+}
+
+func TestTree(t *testing.T) {
+	tests := map[string]struct {
+		inserts []string
+		deletes []string
+		present []string
+		missing []string
+	}{
+		"Single": {
+			[]string{"hello"},
+			nil,
+			[]string{"hello"},
+			[]string{"", "hell", "hello!"},
+		},
+		"Split": {
+			[]string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus"},
+			nil,
+			[]string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus"},
+			[]string{"r", "rom", "roman", "rubic"},
+		},
+		"PrefixKeys": {
+			[]string{"a", "ab", "abc", ""},
+			nil,
+			[]string{"", "a", "ab", "abc"},
+			[]string{"b", "abcd"},
+		},
+		"DeleteMerges": {
+			[]string{"romane", "romanus", "romulus"},
+			[]string{"romanus"},
+			[]string{"romane", "romulus"},
+			[]string{"romanus"},
+		},
+		"DeleteInner": {
+			[]string{"a", "ab", "abc"},
+			[]string{"ab", "a"},
+			[]string{"abc"},
+			[]string{"a", "ab"},
+		},
+		"DeleteAll": {
+			[]string{"test", "team", "toast"},
+			[]string{"test", "team", "toast"},
+			nil,
+			[]string{"test", "team", "toast"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tr := New()
+			for _, k := range test.inserts {
+				if !tr.Insert(k, k) {
+					t.Fatalf("%s: expected insert", k)
+				}
+			}
+			for _, k := range test.deletes {
+				if !tr.Delete(k) {
+					t.Fatalf("%s: expected delete", k)
+				}
+			}
+
+			for _, k := range test.present {
+				v, ok := tr.Get(k)
+				if !ok || v != k {
+					t.Fatalf("%s: expected '%s', got '%v'", k, k, v)
+				}
+			}
+			for _, k := range test.missing {
+				if _, ok := tr.Get(k); ok {
+					t.Fatalf("%s: expected no value", k)
+				}
+				if tr.Delete(k) {
+					t.Fatalf("%s: unexpected delete", k)
+				}
+			}
+
+			if count := validate(t, tr.root, true); count != tr.Len() || count != len(test.present) {
+				t.Fatalf("expected %d keys, got %d and %d", len(test.present), count, tr.Len())
+			}
+			if all := keys(tr.Walk); !isSameSlice(all, test.present) {
+				t.Fatalf("expected %v, got %v", test.present, all)
+			}
+		})
+	}
+}
+
+func TestOverwrite(t *testing.T) {
+	tr := New()
+	tr.Insert("hello", "world")
+	if tr.Insert("hello", "foo") {
+		t.Fatal("expected overwrite")
+	}
+	if v, _ := tr.Get("hello"); v != "foo" {
+		t.Fatalf("expected 'foo', got '%v'", v)
+	}
+	if tr.Len() != 1 {
+		t.Fatalf("expected 1, got %d", tr.Len())
+	}
+}
+
+func TestLongestPrefix(t *testing.T) {
+	tr := New()
+	for _, k := range []string{"foo", "foobar", "foobarbaz", "zip"} {
+		tr.Insert(k, k)
+	}
+
+	tests := map[string]struct {
+		in       string
+		expected string
+		found    bool
+	}{
+		"Exact":     {"foobar", "foobar", true},
+		"Between":   {"foobarba", "foobar", true},
+		"Longest":   {"foobarbazqux", "foobarbaz", true},
+		"Shortest":  {"fooba", "foo", true},
+		"NoMatch":   {"fo", "", false},
+		"OtherEdge": {"zipper", "zip", true},
+		"Empty":     {"", "", false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			k, _, ok := tr.LongestPrefix(test.in)
+			if ok != test.found || k != test.expected {
+				t.Fatalf("expected '%s' %v, got '%s' %v", test.expected, test.found, k, ok)
+			}
+		})
+	}
+}
+
+func TestWalkPrefix(t *testing.T) {
+	tr := New()
+	for _, k := range []string{"foo", "foobar", "foobaz", "fizz", "bar", "zip"} {
+		tr.Insert(k, k)
+	}
+
+	tests := map[string]struct {
+		prefix   string
+		expected []string
+	}{
+		"All":      {"", []string{"bar", "fizz", "foo", "foobar", "foobaz", "zip"}},
+		"Branch":   {"f", []string{"fizz", "foo", "foobar", "foobaz"}},
+		"Key":      {"foo", []string{"foo", "foobar", "foobaz"}},
+		"MidEdge":  {"fooba", []string{"foobar", "foobaz"}},
+		"Exact":    {"foobar", []string{"foobar"}},
+		"NoMatch":  {"fooq", nil},
+		"TooLong":  {"foobarbaz", nil},
+		"Unknown":  {"q", nil},
+		"EdgeOnly": {"zi", []string{"zip"}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			out := keys(func(fn WalkFn) { tr.WalkPrefix(test.prefix, fn) })
+			if !isSameSlice(out, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, out)
+			}
+		})
+	}
+}
+
+func TestWalkPath(t *testing.T) {
+	tr := New()
+	for _, k := range []string{"", "a", "abc", "abd", "b"} {
+		tr.Insert(k, k)
+	}
+
+	out := keys(func(fn WalkFn) { tr.WalkPath("abcde", fn) })
+	if !isSameSlice(out, []string{"", "a", "abc"}) {
+		t.Fatalf("expected [ a abc], got %v", out)
+	}
+
+	var stopped []string
+	tr.WalkPath("abcde", func(k string, v interface{}) bool {
+		stopped = append(stopped, k)
+		return k != "a"
+	})
+	if !isSameSlice(stopped, []string{"", "a"}) {
+		t.Fatalf("expected [ a], got %v", stopped)
+	}
+}
+
+func TestWordList(t *testing.T) {
+	words := append([]string(nil), wordlist.Words...)
+	rand.New(rand.NewSource(42)).Shuffle(len(words), func(i, j int) {
+		words[i], words[j] = words[j], words[i]
+	})
+
+	tr := New()
+	for _, w := range words {
+		tr.Insert(w, len(w))
+	}
+
+	sorted := append([]string(nil), words...)
+	sort.Strings(sorted)
+	if all := keys(tr.Walk); !isSameSlice(all, sorted) {
+		t.Fatal("walk out of order")
+	}
+
+	var expected []string
+	for _, w := range sorted {
+		if strings.HasPrefix(w, "re") {
+			expected = append(expected, w)
+		}
+	}
+	if out := keys(func(fn WalkFn) { tr.WalkPrefix("re", fn) }); !isSameSlice(out, expected) {
+		t.Fatalf("expected %v, got %v", expected, out)
+	}
+
+	for i, w := range words {
+		if i%2 == 0 {
+			tr.Delete(w)
+		}
+	}
+	validate(t, tr.root, true)
+	for i, w := range words {
+		if _, ok := tr.Get(w); ok != (i%2 == 1) {
+			t.Fatalf("%s: unexpected presence %v", w, ok)
+		}
+	}
+}
+
+func BenchmarkRadixGet(b *testing.B) {
+	tr := New()
+	for _, w := range wordlist.Words {
+		tr.Insert(w, w)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, w := range wordlist.Words {
+			v, _ := tr.Get(w)
+			_ = v
+		}
+	}
+}
+
+func BenchmarkHashmapLookup(b *testing.B) {
+	m := hashmap.NewXXHashmap()
+	for _, w := range wordlist.Words {
+		m.Add(w, w)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, w := range wordlist.Words {
+			v, _ := m.Lookup(w)
+			_ = v
+		}
+	}
+}
+
+func BenchmarkRadixWalkPrefix(b *testing.B) {
+	tr := New()
+	for _, w := range wordlist.Words {
+		tr.Insert(w, w)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tr.WalkPrefix("re", func(k string, v interface{}) bool { return true })
+	}
+}
+
+func BenchmarkHashmapScanPrefix(b *testing.B) {
+	m := hashmap.NewXXHashmap()
+	for _, w := range wordlist.Words {
+		m.Add(w, w)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Iter(func(k string, v interface{}) bool {
+			_ = strings.HasPrefix(k, "re")
+			return true
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package ring
+
+import "sync"
+
+// Jump places keys using the jump consistent hash from
+// "A Fast, Minimal Memory, Consistent Hash Algorithm" (Lamping, Veach).
+// Adding a node only moves keys onto the new node. Removing a node
+// moves the last node into its slot, so only the keys of those two
+// nodes are reassigned.
+type Jump struct {
+	mu    sync.RWMutex
+	fn    func(string) uint64
+	nodes []string
+}
+
+// NewJump creates a new jump hash using the specified hashing function.
+func NewJump(fn func(string) uint64) *Jump {
+	return &Jump{fn: fn}
+}
+
+// Add appends the node and returns whether or not it was added.
+func (j *Jump) Add(node string) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for i := range j.nodes {
+		if j.nodes[i] == node {
+			return false
+		}
+	}
+	j.nodes = append(j.nodes, node)
+	return true
+}
+
+// Remove removes the node and returns whether or not it was removed.
+func (j *Jump) Remove(node string) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for i := range j.nodes {
+		if j.nodes[i] == node {
+			last := len(j.nodes) - 1
+			j.nodes[i] = j.nodes[last]
+			j.nodes = j.nodes[:last]
+			return true
+		}
+	}
+	return false
+}
+
+// Locate returns the node responsible for the key.
+func (j *Jump) Locate(key string) (string, bool) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	if len(j.nodes) == 0 {
+		return "", false
+	}
+	return j.nodes[jump(j.fn(key), len(j.nodes))], true
+}
+
+// LocateN returns up to n distinct nodes for the key. Replicas are
+// found by rehashing the key until enough distinct nodes are seen.
+func (j *Jump) LocateN(key string, n int) []string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	if n > len(j.nodes) {
+		n = len(j.nodes)
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	var (
+		out  = make([]string, 0, n)
+		seen = make([]bool, len(j.nodes))
+		hash = j.fn(key)
+	)
+	for len(out) < n {
+		idx := jump(hash, len(j.nodes))
+		if !seen[idx] {
+			seen[idx] = true
+			out = append(out, j.nodes[idx])
+		}
+		hash = mix(hash)
+	}
+	return out
+}
+
+// Nodes returns the nodes in bucket order.
+func (j *Jump) Nodes() []string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	return append([]string(nil), j.nodes...)
+}
+
+func jump(key uint64, buckets int) int {
+	var b, i int64 = -1, 0
+	for i < int64(buckets) {
+		b = i
+		key = key*2862933555777941757 + 1
+		i = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+// mix is the splitmix64 finalizer.
+func mix(h uint64) uint64 {
+	h += 0x9e3779b97f4a7c15
+	h = (h ^ (h >> 30)) * 0xbf58476d1ce4e5b9
+	h = (h ^ (h >> 27)) * 0x94d049bb133111eb
+	return h ^ (h >> 31)
+}
@@ -0,0 +1,112 @@
+package ring
+
+import (
+	"sort"
+	"sync"
+)
+
+// Rendezvous places keys using highest random weight hashing. Each
+// key is scored against every node and the highest score wins, so
+// only the keys of an added or removed node move.
+type Rendezvous struct {
+	mu     sync.RWMutex
+	fn     func(string) uint64
+	nodes  []string
+	hashes []uint64
+}
+
+// NewRendezvous creates a new rendezvous hash using the specified hashing function.
+func NewRendezvous(fn func(string) uint64) *Rendezvous {
+	return &Rendezvous{fn: fn}
+}
+
+// Add adds the node and returns whether or not it was added.
+func (r *Rendezvous) Add(node string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.nodes {
+		if r.nodes[i] == node {
+			return false
+		}
+	}
+	r.nodes = append(r.nodes, node)
+	r.hashes = append(r.hashes, r.fn(node))
+	return true
+}
+
+// Remove removes the node and returns whether or not it was removed.
+func (r *Rendezvous) Remove(node string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.nodes {
+		if r.nodes[i] == node {
+			r.nodes = append(r.nodes[:i], r.nodes[i+1:]...)
+			r.hashes = append(r.hashes[:i], r.hashes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Locate returns the node with the highest score for the key.
+func (r *Rendezvous) Locate(key string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.nodes) == 0 {
+		return "", false
+	}
+
+	var (
+		hash = r.fn(key)
+		best int
+		max  uint64
+	)
+	for i := range r.hashes {
+		if score := mix(hash ^ r.hashes[i]); score > max || i == 0 {
+			best, max = i, score
+		}
+	}
+	return r.nodes[best], true
+}
+
+// LocateN returns the n nodes with the highest scores for the key,
+// highest first.
+func (r *Rendezvous) LocateN(key string, n int) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if n > len(r.nodes) {
+		n = len(r.nodes)
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	hash := r.fn(key)
+	idx := make([]int, len(r.nodes))
+	scores := make([]uint64, len(r.nodes))
+	for i := range r.hashes {
+		idx[i] = i
+		scores[i] = mix(hash ^ r.hashes[i])
+	}
+	sort.Slice(idx, func(i, j int) bool {
+		return scores[idx[i]] > scores[idx[j]]
+	})
+
+	out := make([]string, n)
+	for i := range out {
+		out[i] = r.nodes[idx[i]]
+	}
+	return out
+}
+
+// Nodes returns the nodes in the order they were added.
+func (r *Rendezvous) Nodes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]string(nil), r.nodes...)
+}
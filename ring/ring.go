@@ -0,0 +1,260 @@
+package ring
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/cespare/xxhash"
+	"github.com/segmentio/fasthash/fnv1a"
+)
+
+var (
+	_ Strategy = &Ring{}
+	_ Strategy = &Jump{}
+	_ Strategy = &Rendezvous{}
+)
+
+// Strategy is an interface for placing keys on a set of nodes.
+type Strategy interface {
+	Add(node string) bool
+	Remove(node string) bool
+	Locate(key string) (string, bool)
+	LocateN(key string, n int) []string
+	Nodes() []string
+}
+
+type point struct {
+	hash uint64
+	node string
+}
+
+// Ring is a consistent hashing ring. Each node is placed on the
+// ring a number of times (virtual nodes) to even out the distribution
+// of keys.
+type Ring struct {
+	mu       sync.RWMutex
+	fn       func(string) uint64
+	replicas int
+	points   []point
+	nodes    []string
+
+	// bounded loads, only used when factor > 0
+	factor float64
+	loads  map[string]int
+	total  int
+}
+
+// NewFNV1aRing returns a ring using the fnv1a hashing function.
+func NewFNV1aRing(replicas int) *Ring {
+	return NewRing(fnv1a.HashString64, replicas)
+}
+
+// NewXXRing returns a ring using the xxhash hashing function.
+func NewXXRing(replicas int) *Ring {
+	return NewRing(xxhash.Sum64String, replicas)
+}
+
+// NewRing creates a new, empty, ring which places each node
+// replicas times using the specified hashing function.
+func NewRing(fn func(string) uint64, replicas int) *Ring {
+	if replicas < 1 {
+		replicas = 1
+	}
+	return &Ring{fn: fn, replicas: replicas}
+}
+
+// NewBoundedRing creates a new ring with bounded loads. No node is
+// assigned more than ceil(factor * average load) keys, where the loads
+// are tracked with Acquire and Release. The factor must be greater than 1.
+func NewBoundedRing(fn func(string) uint64, replicas int, factor float64) *Ring {
+	if factor <= 1 {
+		panic("invalid load factor")
+	}
+	r := NewRing(fn, replicas)
+	r.factor = factor
+	r.loads = make(map[string]int)
+	return r
+}
+
+// Add places the node on the ring and returns whether or not it was added.
+func (r *Ring) Add(node string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.index(node) >= 0 {
+		return false
+	}
+
+	r.nodes = append(r.nodes, node)
+	for i := 0; i < r.replicas; i++ {
+		r.points = append(r.points, point{r.fn(node + "#" + strconv.Itoa(i)), node})
+	}
+	sort.Slice(r.points, func(i, j int) bool {
+		if r.points[i].hash == r.points[j].hash {
+			return r.points[i].node < r.points[j].node
+		}
+		return r.points[i].hash < r.points[j].hash
+	})
+	return true
+}
+
+// Remove takes the node off the ring and returns whether or not it was removed.
+func (r *Ring) Remove(node string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	idx := r.index(node)
+	if idx < 0 {
+		return false
+	}
+	r.nodes = append(r.nodes[:idx], r.nodes[idx+1:]...)
+
+	points := r.points[:0]
+	for i := range r.points {
+		if r.points[i].node != node {
+			points = append(points, r.points[i])
+		}
+	}
+	r.points = points
+
+	if r.loads != nil {
+		r.total -= r.loads[node]
+		delete(r.loads, node)
+	}
+	return true
+}
+
+// Locate returns the node responsible for the key. In bounded mode
+// nodes which are at capacity are skipped.
+func (r *Ring) Locate(key string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.locate(key)
+}
+
+// LocateN returns up to n distinct nodes for the key, in ring order.
+// The first node is the one returned by Locate.
+func (r *Ring) LocateN(key string, n int) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if n > len(r.nodes) {
+		n = len(r.nodes)
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	var (
+		out  = make([]string, 0, n)
+		seen = make(map[string]struct{}, n)
+		max  = r.capacity()
+	)
+	start := r.search(r.fn(key))
+	for i := 0; i < len(r.points) && len(out) < n; i++ {
+		node := r.points[(start+i)%len(r.points)].node
+		if _, ok := seen[node]; ok {
+			continue
+		}
+		seen[node] = struct{}{}
+		if r.loads != nil && r.loads[node] >= max {
+			continue
+		}
+		out = append(out, node)
+	}
+	return out
+}
+
+// Acquire locates the node for the key and increments its load.
+// Acquire is only meaningful for a bounded ring.
+func (r *Ring) Acquire(key string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	node, ok := r.locate(key)
+	if ok && r.loads != nil {
+		r.loads[node]++
+		r.total++
+	}
+	return node, ok
+}
+
+// Release decrements the load of the node.
+func (r *Ring) Release(node string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.loads == nil || r.loads[node] == 0 {
+		return
+	}
+	r.loads[node]--
+	r.total--
+}
+
+// Load returns the current load of the node.
+func (r *Ring) Load(node string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.loads[node]
+}
+
+// Nodes returns the nodes on the ring, in the order they were added.
+func (r *Ring) Nodes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]string(nil), r.nodes...)
+}
+
+func (r *Ring) locate(key string) (string, bool) {
+	if len(r.points) == 0 {
+		return "", false
+	}
+
+	start := r.search(r.fn(key))
+	if r.loads == nil {
+		return r.points[start].node, true
+	}
+
+	max := r.capacity()
+	for i := 0; i < len(r.points); i++ {
+		node := r.points[(start+i)%len(r.points)].node
+		if r.loads[node] < max {
+			return node, true
+		}
+	}
+	return "", false
+}
+
+// search returns the index of the first point clockwise from hash.
+func (r *Ring) search(hash uint64) int {
+	idx := sort.Search(len(r.points), func(i int) bool {
+		return r.points[i].hash >= hash
+	})
+	if idx == len(r.points) {
+		idx = 0
+	}
+	return idx
+}
+
+// capacity returns the maximum load allowed per node when
+// one more key is placed.
+func (r *Ring) capacity() int {
+	if len(r.nodes) == 0 {
+		return 0
+	}
+	return int(math.Ceil(r.factor * float64(r.total+1) / float64(len(r.nodes))))
+}
+
+func (r *Ring) index(node string) int {
+	for i := range r.nodes {
+		if r.nodes[i] == node {
+			return i
+		}
+	}
+	return -1
+}
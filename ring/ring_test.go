@@ -0,0 +1,215 @@
+package ring
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/cespare/xxhash"
+	"github.com/segmentio/fasthash/fnv1a"
+)
+
+func strategies() map[string]func() Strategy {
+	return map[string]func() Strategy{
+		"FNV1aRing":  func() Strategy { return NewFNV1aRing(100) },
+		"XXRing":     func() Strategy { return NewXXRing(100) },
+		"Jump":       func() Strategy { return NewJump(xxhash.Sum64String) },
+		"Rendezvous": func() Strategy { return NewRendezvous(fnv1a.HashString64) },
+	}
+}
+
+func keys(n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = "key-" + strconv.Itoa(i)
+	}
+	return out
+}
+
+func TestEmpty(t *testing.T) {
+	for name, fn := range strategies() {
+		t.Run(name, func(t *testing.T) {
+			s := fn()
+			if _, ok := s.Locate("hello"); ok {
+				t.Fatal("expected no node")
+			}
+			if out := s.LocateN("hello", 3); len(out) != 0 {
+				t.Fatalf("expected no nodes, got %v", out)
+			}
+		})
+	}
+}
+
+func TestAddRemove(t *testing.T) {
+	for name, fn := range strategies() {
+		t.Run(name, func(t *testing.T) {
+			s := fn()
+			if !s.Add("a") || !s.Add("b") || s.Add("a") {
+				t.Fatal("unexpected add result")
+			}
+			if len(s.Nodes()) != 2 {
+				t.Fatalf("expected 2 nodes, got %v", s.Nodes())
+			}
+			if !s.Remove("a") || s.Remove("a") {
+				t.Fatal("unexpected remove result")
+			}
+
+			for _, k := range keys(100) {
+				node, ok := s.Locate(k)
+				if !ok || node != "b" {
+					t.Fatalf("%s: expected b, got %s", k, node)
+				}
+			}
+		})
+	}
+}
+
+func TestLocateN(t *testing.T) {
+	for name, fn := range strategies() {
+		t.Run(name, func(t *testing.T) {
+			s := fn()
+			for i := 0; i < 5; i++ {
+				s.Add("node-" + strconv.Itoa(i))
+			}
+
+			for _, k := range keys(100) {
+				out := s.LocateN(k, 3)
+				if len(out) != 3 {
+					t.Fatalf("%s: expected 3 nodes, got %v", k, out)
+				}
+
+				node, _ := s.Locate(k)
+				if out[0] != node {
+					t.Fatalf("%s: expected %s first, got %v", k, node, out)
+				}
+
+				seen := make(map[string]bool)
+				for _, n := range out {
+					if seen[n] {
+						t.Fatalf("%s: duplicate node in %v", k, out)
+					}
+					seen[n] = true
+				}
+			}
+
+			if out := s.LocateN("hello", 10); len(out) != 5 {
+				t.Fatalf("expected 5 nodes, got %v", out)
+			}
+		})
+	}
+}
+
+func TestMovement(t *testing.T) {
+	in := keys(10000)
+
+	for name, fn := range strategies() {
+		t.Run(name, func(t *testing.T) {
+			s := fn()
+			for i := 0; i < 10; i++ {
+				s.Add("node-" + strconv.Itoa(i))
+			}
+
+			before := make(map[string]string)
+			for _, k := range in {
+				before[k], _ = s.Locate(k)
+			}
+
+			s.Add("node-10")
+
+			var moved int
+			for _, k := range in {
+				node, _ := s.Locate(k)
+				if node == before[k] {
+					continue
+				}
+				if node != "node-10" {
+					t.Fatalf("%s: moved from %s to %s", k, before[k], node)
+				}
+				moved++
+			}
+
+			// Expect roughly 1/11 of the keys to move.
+			if moved == 0 || moved > len(in)/5 {
+				t.Fatalf("expected around %d keys to move, got %d", len(in)/11, moved)
+			}
+		})
+	}
+}
+
+func TestBoundedLoads(t *testing.T) {
+	r := NewBoundedRing(fnv1a.HashString64, 10, 1.25)
+	for i := 0; i < 4; i++ {
+		r.Add("node-" + strconv.Itoa(i))
+	}
+
+	// Every key hashes to the same place, so without bounds
+	// a single node would take all of them.
+	same := NewBoundedRing(func(string) uint64 { return 42 }, 10, 1.25)
+	for i := 0; i < 4; i++ {
+		same.Add("node-" + strconv.Itoa(i))
+	}
+
+	for name, r := range map[string]*Ring{"Distributed": r, "Skewed": same} {
+		t.Run(name, func(t *testing.T) {
+			in := keys(1000)
+			for _, k := range in {
+				if _, ok := r.Acquire(k); !ok {
+					t.Fatalf("%s: expected a node", k)
+				}
+			}
+
+			max := (len(in)*125/100 + 3) / 4
+			for _, node := range r.Nodes() {
+				if l := r.Load(node); l > max {
+					t.Fatalf("%s: load %d exceeds %d", node, l, max)
+				}
+			}
+
+			for _, node := range r.Nodes() {
+				for r.Load(node) > 0 {
+					r.Release(node)
+				}
+			}
+			if r.total != 0 {
+				t.Fatalf("expected no load, got %d", r.total)
+			}
+		})
+	}
+}
+
+func BenchmarkLocate(b *testing.B) {
+	in := keys(1000)
+
+	for name, fn := range strategies() {
+		b.Run(name, func(b *testing.B) {
+			s := fn()
+			for i := 0; i < 50; i++ {
+				s.Add("node-" + strconv.Itoa(i))
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				s.Locate(in[i%len(in)])
+			}
+		})
+	}
+}
+
+func BenchmarkLocateN(b *testing.B) {
+	in := keys(1000)
+
+	for name, fn := range strategies() {
+		b.Run(name, func(b *testing.B) {
+			s := fn()
+			for i := 0; i < 50; i++ {
+				s.Add("node-" + strconv.Itoa(i))
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				s.LocateN(in[i%len(in)], 3)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package segmenttree
+
+// Number is a type which supports addition and multiplication.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// Sum returns a Monoid which adds values together.
+func Sum[T Number]() Monoid[T] {
+	return Monoid[T]{
+		Identity: 0,
+		Combine:  func(a, b T) T { return a + b },
+	}
+}
+
+// Min returns a Monoid which keeps the least value. The identity must
+// be at least as large as any value in the tree, such as math.MaxInt
+// or math.Inf(1).
+func Min[T Number](identity T) Monoid[T] {
+	return Monoid[T]{
+		Identity: identity,
+		Combine:  func(a, b T) T { return min(a, b) },
+	}
+}
+
+// Max returns a Monoid which keeps the greatest value. The identity
+// must be no larger than any value in the tree, such as math.MinInt
+// or math.Inf(-1).
+func Max[T Number](identity T) Monoid[T] {
+	return Monoid[T]{
+		Identity: identity,
+		Combine:  func(a, b T) T { return max(a, b) },
+	}
+}
+
+// AddToSum returns an Update which adds to every value in a range
+// of a tree built with Sum, increasing their sum by n times as much.
+func AddToSum[T Number]() Update[T, T] {
+	return Update[T, T]{
+		Apply:   func(sum, delta T, n int) T { return sum + delta*T(n) },
+		Compose: func(first, second T) T { return first + second },
+	}
+}
+
+// AddToExtremum returns an Update which adds to every value in a range
+// of a tree built with Min or Max, which moves the extremum as much.
+func AddToExtremum[T Number]() Update[T, T] {
+	return Update[T, T]{
+		Apply:   func(extremum, delta T, n int) T { return extremum + delta },
+		Compose: func(first, second T) T { return first + second },
+	}
+}
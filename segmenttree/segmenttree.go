@@ -0,0 +1,198 @@
+// Package segmenttree implements a segment tree, which answers queries
+// over any range of an array, such as its sum, min or max, and updates
+// single values or whole ranges, each in O(log n). Range updates are
+// applied lazily: a node covering the whole range records the update
+// and only passes it on to its children when they are next visited.
+package segmenttree
+
+// Monoid combines values. Combine must be associative and Identity
+// must leave any value unchanged when combined with it on either side.
+// Combine need not be commutative, as values are always combined in
+// the order they appear in the array.
+type Monoid[T any] struct {
+	Identity T
+	Combine  func(a, b T) T
+}
+
+// Update describes an update of type U applied to every value in a
+// range. Apply returns the aggregate of n values after the update
+// given their aggregate before it, and Compose returns the single
+// update equivalent to first followed by second.
+//
+// The zero Update can be used for a tree which only needs point
+// updates, in which case UpdateRange panics.
+type Update[T, U any] struct {
+	Apply   func(aggregate T, update U, n int) T
+	Compose func(first, second U) U
+}
+
+// Tree is a segment tree over values of type T with range updates of
+// type U. Indexes count from zero and ranges are half open, so
+// Query(lo, hi) covers the values at lo up to but not including hi.
+//
+// A query may pass pending updates down the tree, so a Tree isn't safe
+// for concurrent use, even if it is only being read.
+type Tree[T, U any] struct {
+	monoid Monoid[T]
+	update Update[T, U]
+	n      int
+
+	// The node at i covers a range of the array and its children
+	// at 2i and 2i+1 cover its two halves. The root is at 1.
+	agg     []T
+	lazy    []U
+	pending []bool
+}
+
+// New returns a tree holding a copy of values, built in O(n).
+func New[T, U any](values []T, m Monoid[T], u Update[T, U]) *Tree[T, U] {
+	t := &Tree[T, U]{
+		monoid:  m,
+		update:  u,
+		n:       len(values),
+		agg:     make([]T, 4*len(values)),
+		lazy:    make([]U, 4*len(values)),
+		pending: make([]bool, 4*len(values)),
+	}
+	if t.n > 0 {
+		t.build(1, 0, t.n, values)
+	}
+	return t
+}
+
+// Len returns the number of values in the tree.
+func (t *Tree[T, U]) Len() int {
+	return t.n
+}
+
+// Get returns the value at index i.
+func (t *Tree[T, U]) Get(i int) T {
+	return t.Query(i, i+1)
+}
+
+// Set replaces the value at index i.
+func (t *Tree[T, U]) Set(i int, value T) {
+	if i < 0 || i >= t.n {
+		panic("index out of range")
+	}
+	t.set(1, 0, t.n, i, value)
+}
+
+// Query returns the values between lo and hi combined in order,
+// or the identity if the range is empty.
+func (t *Tree[T, U]) Query(lo, hi int) T {
+	t.checkRange(lo, hi)
+	if lo == hi {
+		return t.monoid.Identity
+	}
+	return t.query(1, 0, t.n, lo, hi)
+}
+
+// UpdateRange applies the update to every value between lo and hi.
+func (t *Tree[T, U]) UpdateRange(lo, hi int, update U) {
+	if t.update.Apply == nil {
+		panic("tree has no range update")
+	}
+	t.checkRange(lo, hi)
+	if lo < hi {
+		t.updateRange(1, 0, t.n, lo, hi, update)
+	}
+}
+
+func (t *Tree[T, U]) checkRange(lo, hi int) {
+	if lo < 0 || hi > t.n || lo > hi {
+		panic("range out of bounds")
+	}
+}
+
+// The methods below work on the node at i covering the range l to r.
+
+func (t *Tree[T, U]) build(i, l, r int, values []T) {
+	if r-l == 1 {
+		t.agg[i] = values[l]
+		return
+	}
+
+	m := l + (r-l)/2
+	t.build(2*i, l, m, values)
+	t.build(2*i+1, m, r, values)
+	t.agg[i] = t.monoid.Combine(t.agg[2*i], t.agg[2*i+1])
+}
+
+// apply updates the aggregate of the node and, unless it is a leaf,
+// records the update for its children.
+func (t *Tree[T, U]) apply(i, l, r int, u U) {
+	t.agg[i] = t.update.Apply(t.agg[i], u, r-l)
+	if r-l == 1 {
+		return
+	}
+
+	if t.pending[i] {
+		t.lazy[i] = t.update.Compose(t.lazy[i], u)
+	} else {
+		t.lazy[i] = u
+		t.pending[i] = true
+	}
+}
+
+// push passes a pending update on to the node's children.
+func (t *Tree[T, U]) push(i, l, m, r int) {
+	if !t.pending[i] {
+		return
+	}
+
+	t.apply(2*i, l, m, t.lazy[i])
+	t.apply(2*i+1, m, r, t.lazy[i])
+	var zero U
+	t.lazy[i] = zero
+	t.pending[i] = false
+}
+
+func (t *Tree[T, U]) set(i, l, r, pos int, value T) {
+	if r-l == 1 {
+		t.agg[i] = value
+		return
+	}
+
+	m := l + (r-l)/2
+	t.push(i, l, m, r)
+	if pos < m {
+		t.set(2*i, l, m, pos, value)
+	} else {
+		t.set(2*i+1, m, r, pos, value)
+	}
+	t.agg[i] = t.monoid.Combine(t.agg[2*i], t.agg[2*i+1])
+}
+
+func (t *Tree[T, U]) query(i, l, r, lo, hi int) T {
+	if lo <= l && r <= hi {
+		return t.agg[i]
+	}
+
+	m := l + (r-l)/2
+	t.push(i, l, m, r)
+	switch {
+	case hi <= m:
+		return t.query(2*i, l, m, lo, hi)
+	case lo >= m:
+		return t.query(2*i+1, m, r, lo, hi)
+	}
+	return t.monoid.Combine(t.query(2*i, l, m, lo, hi), t.query(2*i+1, m, r, lo, hi))
+}
+
+func (t *Tree[T, U]) updateRange(i, l, r, lo, hi int, u U) {
+	if lo <= l && r <= hi {
+		t.apply(i, l, r, u)
+		return
+	}
+
+	m := l + (r-l)/2
+	t.push(i, l, m, r)
+	if lo < m {
+		t.updateRange(2*i, l, m, lo, hi, u)
+	}
+	if hi > m {
+		t.updateRange(2*i+1, m, r, lo, hi, u)
+	}
+	t.agg[i] = t.monoid.Combine(t.agg[2*i], t.agg[2*i+1])
+}
@@ -0,0 +1,242 @@
+package segmenttree
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// affine maps x to a*x + b.
+type affine struct{ a, b int }
+
+var affineSum = Update[int, affine]{
+	Apply: func(sum int, u affine, n int) int { return u.a*sum + u.b*n },
+	// second(first(x)) = a2(a1x + b1) + b2
+	Compose: func(first, second affine) affine {
+		return affine{second.a * first.a, second.a*first.b + second.b}
+	},
+}
+
+// model is the brute force equivalent of a tree: a slice, a function
+// to combine a range of it and one to update a value.
+type model struct {
+	values  []int
+	combine func([]int) int
+	update  func(int, int) int
+}
+
+func TestDifferential(t *testing.T) {
+	tests := map[string]struct {
+		new   func([]int) *Tree[int, int]
+		model model
+	}{
+		"Sum": {
+			func(v []int) *Tree[int, int] { return New(v, Sum[int](), AddToSum[int]()) },
+			model{
+				combine: func(v []int) int {
+					sum := 0
+					for _, x := range v {
+						sum += x
+					}
+					return sum
+				},
+				update: func(x, delta int) int { return x + delta },
+			},
+		},
+		"Min": {
+			func(v []int) *Tree[int, int] { return New(v, Min(math.MaxInt), AddToExtremum[int]()) },
+			model{
+				combine: func(v []int) int {
+					m := math.MaxInt
+					for _, x := range v {
+						m = min(m, x)
+					}
+					return m
+				},
+				update: func(x, delta int) int { return x + delta },
+			},
+		},
+		"Max": {
+			func(v []int) *Tree[int, int] { return New(v, Max(math.MinInt), AddToExtremum[int]()) },
+			model{
+				combine: func(v []int) int {
+					m := math.MinInt
+					for _, x := range v {
+						m = max(m, x)
+					}
+					return m
+				},
+				update: func(x, delta int) int { return x + delta },
+			},
+		},
+		"SumAssign": {
+			func(v []int) *Tree[int, int] {
+				return New(v, Sum[int](), Update[int, int]{
+					Apply:   func(sum, value, n int) int { return value * n },
+					Compose: func(first, second int) int { return second },
+				})
+			},
+			model{
+				combine: func(v []int) int {
+					sum := 0
+					for _, x := range v {
+						sum += x
+					}
+					return sum
+				},
+				update: func(x, value int) int { return value },
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			for _, n := range []int{0, 1, 2, 3, 7, 64, 100} {
+				values := make([]int, n)
+				for i := range values {
+					values[i] = r.Intn(201) - 100
+				}
+				m := test.model
+				m.values = append([]int(nil), values...)
+				tr := test.new(values)
+
+				for op := 0; op < 2000; op++ {
+					lo := r.Intn(n + 1)
+					hi := lo + r.Intn(n-lo+1)
+					switch r.Intn(4) {
+					case 0:
+						if n == 0 {
+							continue
+						}
+						i, v := r.Intn(n), r.Intn(201)-100
+						tr.Set(i, v)
+						m.values[i] = v
+					case 1:
+						u := r.Intn(21) - 10
+						tr.UpdateRange(lo, hi, u)
+						for i := lo; i < hi; i++ {
+							m.values[i] = m.update(m.values[i], u)
+						}
+					default:
+						if got, expected := tr.Query(lo, hi), m.combine(m.values[lo:hi]); got != expected {
+							t.Fatalf("n=%d op=%d: query [%d, %d): expected %d, got %d", n, op, lo, hi, expected, got)
+						}
+					}
+				}
+
+				for i, v := range m.values {
+					if got := tr.Get(i); got != v {
+						t.Fatalf("n=%d: index %d: expected %d, got %d", n, i, v, got)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestComposeOrder uses updates which don't commute, so applying a
+// pending update in the wrong order gives the wrong answer.
+func TestComposeOrder(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	values := make([]int, 50)
+	for i := range values {
+		values[i] = r.Intn(10)
+	}
+	tr := New(append([]int(nil), values...), Sum[int](), affineSum)
+
+	for op := 0; op < 1000; op++ {
+		lo := r.Intn(len(values))
+		hi := lo + 1 + r.Intn(len(values)-lo)
+		u := affine{r.Intn(3) - 1, r.Intn(5) - 2}
+		tr.UpdateRange(lo, hi, u)
+		for i := lo; i < hi; i++ {
+			values[i] = u.a*values[i] + u.b
+		}
+
+		qlo := r.Intn(len(values))
+		qhi := qlo + r.Intn(len(values)-qlo+1)
+		expected := 0
+		for _, v := range values[qlo:qhi] {
+			expected += v
+		}
+		if got := tr.Query(qlo, qhi); got != expected {
+			t.Fatalf("op %d: query [%d, %d): expected %d, got %d", op, qlo, qhi, expected, got)
+		}
+	}
+}
+
+// TestNonCommutative checks that values are combined in array order.
+func TestNonCommutative(t *testing.T) {
+	concat := Monoid[string]{Combine: func(a, b string) string { return a + b }}
+	words := strings.Fields("the quick brown fox jumps over the lazy dog")
+	tr := New(words, concat, Update[string, struct{}]{})
+
+	for lo := 0; lo <= len(words); lo++ {
+		for hi := lo; hi <= len(words); hi++ {
+			if got, expected := tr.Query(lo, hi), strings.Join(words[lo:hi], ""); got != expected {
+				t.Fatalf("query [%d, %d): expected %q, got %q", lo, hi, expected, got)
+			}
+		}
+	}
+
+	tr.Set(2, "red")
+	if got := tr.Query(1, 4); got != "quickredfox" {
+		t.Fatalf("expected quickredfox, got %q", got)
+	}
+}
+
+func TestPanics(t *testing.T) {
+	tr := New([]int{1, 2, 3}, Sum[int](), AddToSum[int]())
+	point := New([]int{1, 2, 3}, Sum[int](), Update[int, int]{})
+
+	tests := map[string]func(){
+		"SetNegative":   func() { tr.Set(-1, 0) },
+		"SetPastEnd":    func() { tr.Set(3, 0) },
+		"GetPastEnd":    func() { tr.Get(3) },
+		"QueryReversed": func() { tr.Query(2, 1) },
+		"QueryPastEnd":  func() { tr.Query(0, 4) },
+		"UpdatePastEnd": func() { tr.UpdateRange(1, 4, 1) },
+		"NoUpdate":      func() { point.UpdateRange(0, 1, 1) },
+	}
+	for name, fn := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected a panic")
+				}
+			}()
+			fn()
+		})
+	}
+
+	if got := tr.Query(1, 1); got != 0 {
+		t.Fatalf("expected the identity for an empty range, got %d", got)
+	}
+}
+
+func BenchmarkSegmentTree(b *testing.B) {
+	for _, n := range []int{1 << 10, 1 << 20} {
+		values := make([]int, n)
+		for i := range values {
+			values[i] = i
+		}
+		tr := New(values, Sum[int](), AddToSum[int]())
+		r := rand.New(rand.NewSource(1))
+
+		b.Run(fmt.Sprintf("Query/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				lo := r.Intn(n)
+				tr.Query(lo, lo+r.Intn(n-lo))
+			}
+		})
+		b.Run(fmt.Sprintf("UpdateRange/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				lo := r.Intn(n)
+				tr.UpdateRange(lo, lo+r.Intn(n-lo), 1)
+			}
+		})
+	}
+}